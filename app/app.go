@@ -62,7 +62,8 @@ const (
 
 // App is a lightweight backend application lifecycle manager.
 type App struct {
-	mu sync.Mutex
+	mu       sync.Mutex
+	reloadMu sync.Mutex
 
 	name    atomic.Value
 	commit  atomic.Value
@@ -71,19 +72,31 @@ type App struct {
 
 	configLoader    Hook
 	shutdownTimeout time.Duration
+	moduleWorkers   int
+	stageTimeouts   map[Stage]time.Duration
 	signals         []os.Signal
+	reloadSignals   []os.Signal
+	notifier        SignalNotifier
 
 	modules []Module
+	sorted  []Module
 	hooks   map[Stage][]namedHook
 	stage   Stage
 	state   state
 
+	ready     atomic.Bool
+	health    healthRegistry
+	status    statusRegistry
+	observers observers
+
 	runCtx    context.Context
 	cancelRun context.CancelFunc
 
-	wg    sync.WaitGroup
-	errCh chan error
-	done  chan struct{}
+	wg        sync.WaitGroup
+	abandoned sync.WaitGroup
+	lateInits []Module
+	errCh     chan error
+	done      chan struct{}
 }
 
 // New creates an App with minimal default behavior.
@@ -94,17 +107,24 @@ type App struct {
 //   - uses a minimal flag-based ConfigLoader
 //   - uses 30 seconds as shutdown timeout
 //   - listens to SIGINT, SIGTERM
+//   - listens to SIGHUP to reload on unix
+//   - notifies systemd of the readiness and stopping if $NOTIFY_SOCKET is set
 func New() *App {
 	app := &App{
 		state: stateNew,
 		hooks: make(map[Stage][]namedHook),
 		done:  make(chan struct{}),
+
+		stageTimeouts: make(map[Stage]time.Duration),
+		notifier:      osSignalNotifier{},
 	}
 	app.SetName(strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe"))
 	app.SetConfigLoader(defaultFlagConfigLoader)
 	app.SetShutdownTimeout(30 * time.Second)
 	app.SetSignals(os.Interrupt, syscall.SIGTERM)
+	app.SetReloadSignals(defaultReloadSignals...)
 	app.SetVersion("0.0.0")
+	app.SetCommit("")
 	return app
 }
 
@@ -226,6 +246,36 @@ func (a *App) SetSignals(signals ...os.Signal) {
 	a.signals = slices.Clone(signals)
 }
 
+// SignalNotifier relays the incoming signals to the channel,
+// which has the same semantics as the functions of the package os/signal.
+type SignalNotifier interface {
+	Notify(c chan<- os.Signal, sig ...os.Signal)
+	Stop(c chan<- os.Signal)
+}
+
+type osSignalNotifier struct{}
+
+func (osSignalNotifier) Notify(c chan<- os.Signal, sig ...os.Signal) { signal.Notify(c, sig...) }
+func (osSignalNotifier) Stop(c chan<- os.Signal)                     { signal.Stop(c) }
+
+// SetSignalNotifier replaces the notifier of the signals set by SetSignals
+// and SetReloadSignals, which may be used to deliver the fake signals in tests.
+//
+// Default: the package os/signal.
+//
+// It must be called before Run.
+func (a *App) SetSignalNotifier(notifier SignalNotifier) {
+	if notifier == nil {
+		panic("app: nil signal notifier")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.mustBeNewLocked("SetSignalNotifier")
+	a.notifier = notifier
+}
+
 // Run starts the app lifecycle and blocks until shutdown,
 // which can only be called once.
 //
@@ -234,10 +284,13 @@ func (a *App) Run(ctx context.Context) (err error) {
 	runCtx, cancelRun := context.WithCancel(ctx)
 	defer cancelRun()
 
-	modules, loader, signals := a.startRun(runCtx, cancelRun)
+	modules, loader, signals, reloadSignals := a.startRun(runCtx, cancelRun)
 
-	signalCtx, stopSignal := signal.NotifyContext(runCtx, signals...)
-	defer stopSignal()
+	sigCh := make(chan os.Signal, 2)
+	if allSignals := slices.Concat(signals, reloadSignals); len(allSignals) > 0 {
+		a.notifier.Notify(sigCh, allSignals...)
+		defer a.notifier.Stop(sigCh)
+	}
 
 	initialized := make([]Module, 0, len(modules))
 	shutdownDone := false
@@ -250,6 +303,7 @@ func (a *App) Run(ctx context.Context) (err error) {
 		shutdownDone = true
 
 		a.markStopping()
+		a.sdNotify(SdNotifyStopping)
 		cancelRun()
 
 		shutdownCtx, cancelShutdown := a.newShutdownContext()
@@ -257,6 +311,18 @@ func (a *App) Run(ctx context.Context) (err error) {
 
 		var shutdownErr error
 
+		// The in-flight reload has been canceled by cancelRun.
+		if e := a.waitReload(shutdownCtx); e != nil {
+			shutdownErr = errors.Join(shutdownErr, e)
+		}
+
+		// The init and start calls abandoned on timeout must finish
+		// before the modules stop, and the late initialized ones stop, too.
+		if e := a.waitAbandoned(shutdownCtx); e != nil {
+			shutdownErr = errors.Join(shutdownErr, e)
+		}
+		initialized = append(initialized, a.takeLateInits()...)
+
 		if e := a.runHooks(shutdownCtx, StageStopping); e != nil {
 			shutdownErr = errors.Join(shutdownErr, e)
 		}
@@ -284,51 +350,98 @@ func (a *App) Run(ctx context.Context) (err error) {
 		}
 	}()
 
+	graph, err := resolveModules(modules)
+	if err != nil {
+		return err
+	}
+	a.setModules(graph.modules)
+
 	// 1. Config
 	if err = loader(runCtx, a); err != nil {
 		return err
 	}
 
 	// 2. Init hooks
-	if err = a.runHooks(runCtx, StageInit); err != nil {
+	initCtx, cancelInit := a.newStageContext(runCtx, StageInit)
+	defer cancelInit()
+
+	if err = a.runHooks(initCtx, StageInit); err != nil {
 		return err
 	}
 
 	// 3. Module Init
-	for _, m := range modules {
-		if e := m.Init(runCtx, a); e != nil {
-			err = fmt.Errorf("app: init module %q: %w", m.Name(), e)
-			return err
+	err = a.runModules(graph, func(m Module) error {
+		if e := a.callModule(initCtx, m, StageInit, m.Init, func() { a.addLateInit(m) }); e != nil {
+			return fmt.Errorf("app: init module %q: %w", m.Name(), e)
 		}
-
+		return nil
+	}, func(m Module) {
 		initialized = append(initialized, m)
+	})
+	if err != nil {
+		return err
 	}
 
 	// 4. Start hooks
-	if err = a.runHooks(runCtx, StageStart); err != nil {
+	startCtx, cancelStart := a.newStageContext(runCtx, StageStart)
+	defer cancelStart()
+
+	if err = a.runHooks(startCtx, StageStart); err != nil {
 		return err
 	}
 
 	// 5. Module Start
-	for _, m := range initialized {
-		if e := m.Start(runCtx, a); e != nil {
-			err = fmt.Errorf("app: start module %q: %w", m.Name(), e)
-			return err
+	err = a.runModules(graph, func(m Module) error {
+		if e := a.callModule(startCtx, m, StageStart, m.Start, nil); e != nil {
+			return fmt.Errorf("app: start module %q: %w", m.Name(), e)
 		}
+		return nil
+	}, nil)
+	if err != nil {
+		return err
 	}
 
 	// 6. Ready hooks
-	if err = a.runHooks(runCtx, StageReady); err != nil {
+	readyCtx, cancelReady := a.newStageContext(runCtx, StageReady)
+	defer cancelReady()
+
+	if err = a.runHooks(readyCtx, StageReady); err != nil {
 		return err
 	}
 
-	// 7. Running
-	select {
-	case <-signalCtx.Done():
-		// Normal shutdown path.
+	a.ready.Store(true)
+	a.sdNotify(SdNotifyReady)
+	a.sdWatchdog(runCtx)
 
-	case e := <-a.errCh:
-		err = errors.Join(err, e)
+	// 7. Running
+	reloadCh := make(chan struct{}, 1)
+	go a.reloadLoop(runCtx, reloadCh)
+
+	for running := true; running; {
+		select {
+		case sig := <-sigCh:
+			a.emit(Event{Kind: EventSignal, Signal: sig})
+
+			if slices.Contains(reloadSignals, sig) {
+				// Keep at most one pending reload besides the in-flight one.
+				select {
+				case reloadCh <- struct{}{}:
+				default:
+				}
+				continue
+			}
+
+			// Normal shutdown path.
+			running = false
+
+		case <-runCtx.Done():
+			// Normal shutdown path.
+			running = false
+
+		case e := <-a.errCh:
+			err = errors.Join(err, e)
+			running = false
+		}
 	}
 
 	// 8. Shutdown
@@ -371,7 +484,7 @@ func (a *App) WaitContext(ctx context.Context) error {
 	}
 }
 
-func (a *App) startRun(ctx context.Context, cancel context.CancelFunc) ([]Module, Hook, []os.Signal) {
+func (a *App) startRun(ctx context.Context, cancel context.CancelFunc) ([]Module, Hook, []os.Signal, []os.Signal) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -386,10 +499,10 @@ func (a *App) startRun(ctx context.Context, cancel context.CancelFunc) ([]Module
 
 	loader := a.configLoader
 	signals := slices.Clone(a.signals)
+	reloadSignals := slices.Clone(a.reloadSignals)
 	modules := slices.Clone(a.modules)
-	sortModules(modules)
 
-	return modules, loader, signals
+	return modules, loader, signals, reloadSignals
 }
 
 func (a *App) shutdown(ctx context.Context, initialized []Module) error {
@@ -399,7 +512,7 @@ func (a *App) shutdown(ctx context.Context, initialized []Module) error {
 	for i := len(initialized) - 1; i >= 0; i-- {
 		m := initialized[i]
 
-		if err := m.Stop(ctx, a); err != nil {
+		if err := a.observeModule(m, "stop", func() error { return m.Stop(ctx, a) }); err != nil {
 			errs = append(errs, fmt.Errorf("app: stop module %q: %w", m.Name(), err))
 		}
 	}
@@ -428,6 +541,20 @@ func (a *App) waitBackground(ctx context.Context) error {
 	}
 }
 
+func (a *App) addLateInit(m Module) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lateInits = append(a.lateInits, m)
+}
+
+func (a *App) takeLateInits() []Module {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	modules := a.lateInits
+	a.lateInits = nil
+	return modules
+}
+
 func (a *App) newShutdownContext() (context.Context, context.CancelFunc) {
 	a.mu.Lock()
 	timeout := a.shutdownTimeout
@@ -437,6 +564,8 @@ func (a *App) newShutdownContext() (context.Context, context.CancelFunc) {
 }
 
 func (a *App) markStopping() {
+	a.ready.Store(false)
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
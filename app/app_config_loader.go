@@ -0,0 +1,278 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/xgfone/go-toolkit/bytex"
+	"github.com/xgfone/go-toolkit/internal/structs"
+	"github.com/xgfone/go-toolkit/jsonx"
+	"github.com/xgfone/go-toolkit/structx"
+	"github.com/xgfone/go-toolkit/validation"
+)
+
+var configParser = structs.NewAnySetterParser("")
+
+// ConfigOption is used to configure the config loader created by NewConfigLoader.
+type ConfigOption func(*configLoader)
+
+// WithConfigFile returns a config option to set the path of the JSON config file.
+//
+// If path is empty, no config file is loaded.
+func WithConfigFile(path string) ConfigOption {
+	return func(c *configLoader) { c.file = path }
+}
+
+// WithConfigFileFlag returns a config option to register a string flag
+// with the name into the flag set, whose value is used as the path
+// of the JSON config file if it is not empty.
+func WithConfigFileFlag(name string) ConfigOption {
+	if name == "" {
+		panic("app: empty config file flag name")
+	}
+	return func(c *configLoader) { c.fileFlag = name }
+}
+
+// WithConfigEnvPrefix returns a config option to set the prefix
+// of the environment variables.
+//
+// If prefix is empty, the environment variables have no prefix.
+//
+// Default: the upper-case app name whose non-alphanumeric characters
+// are replaced with "_".
+func WithConfigEnvPrefix(prefix string) ConfigOption {
+	return func(c *configLoader) { c.envPrefix = &prefix }
+}
+
+// WithConfigFlagSet returns a config option to set the flag set
+// and the command-line arguments to parse.
+//
+// Default: flag.CommandLine and os.Args[1:]
+func WithConfigFlagSet(fs *flag.FlagSet, args []string) ConfigOption {
+	if fs == nil {
+		panic("app: nil config flag set")
+	}
+	return func(c *configLoader) { c.flags, c.args = fs, args }
+}
+
+// WithConfigTag returns a config option to set the struct tag
+// to resolve the config key names of the fields.
+//
+// Default: "json"
+func WithConfigTag(tag string) ConfigOption {
+	return func(c *configLoader) { c.tag = tag }
+}
+
+type configLoader struct {
+	file      string
+	fileFlag  string
+	envPrefix *string
+	flags     *flag.FlagSet
+	args      []string
+	tag       string
+
+	filePath *string
+}
+
+// NewConfigLoader returns a config loader used by App.SetConfigLoader,
+// which merges the config from the sources below into dst in order,
+// so the latter source takes precedence:
+//
+//  1. A JSON config file, whose whole-line and line-tail comments starting
+//     with "#" or "//" are removed by bytex.RemoveLineComments.
+//  2. The environment variables named by the prefix and the upper-case key
+//     path joined by "_", for example, MYAPP_DB_HOST for the key "db.host".
+//     The value of a slice field is split by ",".
+//  3. The command-line flags that are set explicitly and whose names are
+//     the key paths joined by ".", for example, "-db.host".
+//
+// The merged config is bound by structx.BindMap with the tag set by
+// WithConfigTag, then the default values are set by structx.SetDefault,
+// and finally it is validated by validation.Validate. dst is replaced
+// by the new config only if all of them succeed, so the previous config
+// is kept if the loader fails.
+//
+// The flags are parsed only once. If the help flag is given, the process exits.
+func NewConfigLoader[T any](dst *T, opts ...ConfigOption) Hook {
+	if dst == nil {
+		panic("app: nil config")
+	}
+	if reflect.TypeFor[T]().Kind() != reflect.Struct {
+		panic("app: config is not a pointer to struct")
+	}
+
+	c := &configLoader{tag: "json", flags: flag.CommandLine}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.args == nil && c.flags == flag.CommandLine {
+		c.args = os.Args[1:]
+	}
+	if c.fileFlag != "" {
+		c.filePath = c.flags.String(c.fileFlag, c.file, "The path of the config file.")
+	}
+
+	return func(ctx context.Context, app *App) (err error) {
+		var cfg T
+		if err = c.load(app, reflect.TypeFor[T](), &cfg); err != nil {
+			return
+		}
+		*dst = cfg
+		return
+	}
+}
+
+func (c *configLoader) load(app *App, rtype reflect.Type, dst any) (err error) {
+	if !c.flags.Parsed() {
+		if err = c.flags.Parse(c.args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				osexit(0)
+			}
+			return fmt.Errorf("app: parse flags: %w", err)
+		}
+	}
+
+	values := make(map[string]any, 16)
+	if values, err = c.loadFile(values); err != nil {
+		return
+	}
+
+	fields := configParser.Parse(rtype, c.tag).Fields
+	c.loadEnv(app, fields, values)
+	c.loadFlags(values)
+
+	if err = structx.BindMapAny(dst, values, c.tag); err != nil {
+		return fmt.Errorf("app: bind config: %w", err)
+	}
+
+	if err = structx.SetDefaultAny(dst); err != nil {
+		return fmt.Errorf("app: set config defaults: %w", err)
+	}
+
+	if err = validation.Validate(dst); err != nil {
+		return fmt.Errorf("app: validate config: %w", err)
+	}
+
+	return
+}
+
+func (c *configLoader) loadFile(values map[string]any) (map[string]any, error) {
+	path := c.file
+	if c.filePath != nil && *c.filePath != "" {
+		path = *c.filePath
+	}
+
+	if path == "" {
+		return values, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("app: read config file: %w", err)
+	}
+
+	data = bytex.RemoveLineComments(data, bytex.CommentHash)
+	data = bytex.RemoveLineComments(data, bytex.CommentSlashes)
+	if err = jsonx.UnmarshalBytes(data, &values); err != nil {
+		return nil, fmt.Errorf("app: decode config file %s: %w", path, err)
+	}
+
+	if values == nil { // The file content is "null".
+		values = make(map[string]any, 16)
+	}
+
+	return values, nil
+}
+
+func (c *configLoader) loadEnv(app *App, fields []structs.Field[structs.FieldSetter[any]], values map[string]any) {
+	var prefix string
+	if c.envPrefix != nil {
+		prefix = *c.envPrefix
+	} else {
+		prefix = envName(app.Name())
+	}
+
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	for _, f := range fields {
+		names := make([]string, len(f.Names))
+		for i, name := range f.Names {
+			names[i] = envName(name)
+		}
+
+		value, ok := os.LookupEnv(prefix + strings.Join(names, "_"))
+		if !ok {
+			continue
+		}
+
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() != reflect.Uint8 {
+			setConfigValue(values, f.Names, strings.Split(value, ","))
+		} else {
+			setConfigValue(values, f.Names, value)
+		}
+	}
+}
+
+func (c *configLoader) loadFlags(values map[string]any) {
+	c.flags.Visit(func(f *flag.Flag) {
+		if f.Name == c.fileFlag {
+			return
+		}
+
+		var value any
+		if getter, ok := f.Value.(flag.Getter); ok {
+			value = getter.Get()
+		} else {
+			value = f.Value.String()
+		}
+
+		setConfigValue(values, strings.Split(f.Name, "."), value)
+	})
+}
+
+func setConfigValue(values map[string]any, path []string, value any) {
+	for _, name := range path[:len(path)-1] {
+		m, ok := values[name].(map[string]any)
+		if !ok {
+			m = make(map[string]any, 4)
+			values[name] = m
+		}
+		values = m
+	}
+	values[path[len(path)-1]] = value
+}
+
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z':
+			return r - 'a' + 'A'
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
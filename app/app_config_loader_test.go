@@ -0,0 +1,172 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Name    string        `json:"name" default:"demo"`
+	Port    int           `json:"port" default:"80"`
+	Debug   bool          `json:"debug"`
+	Tags    []string      `json:"tags"`
+	Timeout time.Duration `json:"timeout"`
+
+	DB struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	} `json:"db"`
+}
+
+func (c *testConfig) Validate() error {
+	if c.Port > 65535 {
+		return errors.New("invalid port")
+	}
+	return nil
+}
+
+func writeConfigFile(t *testing.T, data string) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewConfigLoader(t *testing.T) {
+	file := writeConfigFile(t, `{
+	# The http port
+	"port": 8080,
+	"debug": true, // Enable the debug mode
+	"tags": ["a", "b"],
+	"db": {"host": "127.0.0.1", "port": 3306}
+}`)
+
+	t.Setenv("MY_APP_DB_HOST", "10.0.0.1")
+	t.Setenv("MY_APP_DB_PORT", "3307")
+	t.Setenv("MY_APP_TAGS", "c,d")
+	t.Setenv("MY_APP_NAME", "env")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("name", "", "")
+	fs.Int("db.port", 0, "")
+	fs.Duration("timeout", 0, "")
+
+	var cfg testConfig
+	loader := NewConfigLoader(&cfg,
+		WithConfigFileFlag("config"),
+		WithConfigFlagSet(fs, []string{"-config", file, "-db.port", "3308", "-timeout", "3s"}),
+	)
+
+	app := New()
+	app.SetName("my-app")
+	if err := loader(context.Background(), app); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "env" {
+		t.Errorf("expect name %q, but got %q", "env", cfg.Name)
+	}
+	if cfg.Port != 8080 || !cfg.Debug {
+		t.Errorf("expect port 8080 and debug, but got %d and %v", cfg.Port, cfg.Debug)
+	}
+	if !slices.Equal(cfg.Tags, []string{"c", "d"}) {
+		t.Errorf("expect tags %v, but got %v", []string{"c", "d"}, cfg.Tags)
+	}
+	if cfg.Timeout != 3*time.Second {
+		t.Errorf("expect timeout %s, but got %s", 3*time.Second, cfg.Timeout)
+	}
+	if cfg.DB.Host != "10.0.0.1" || cfg.DB.Port != 3308 {
+		t.Errorf("expect db 10.0.0.1:3308, but got %s:%d", cfg.DB.Host, cfg.DB.Port)
+	}
+}
+
+func TestNewConfigLoader_Defaults(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	var cfg testConfig
+	loader := NewConfigLoader(&cfg, WithConfigEnvPrefix(""), WithConfigFlagSet(fs, nil))
+	if err := loader(context.Background(), New()); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "demo" || cfg.Port != 80 {
+		t.Errorf("expect defaults demo:80, but got %s:%d", cfg.Name, cfg.Port)
+	}
+}
+
+func TestNewConfigLoader_KeepPreviousOnError(t *testing.T) {
+	file := writeConfigFile(t, `{"port": 70000}`)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	cfg := testConfig{Port: 1234}
+	loader := NewConfigLoader(&cfg, WithConfigFile(file), WithConfigFlagSet(fs, nil))
+
+	err := loader(context.Background(), New())
+	if err == nil || !strings.Contains(err.Error(), "invalid port") {
+		t.Fatalf("expect a validation error, but got %v", err)
+	}
+	if cfg.Port != 1234 {
+		t.Errorf("the previous config should be kept, but got port %d", cfg.Port)
+	}
+}
+
+func TestNewConfigLoader_Errors(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var cfg testConfig
+
+	loader := NewConfigLoader(&cfg, WithConfigFile("/nonexistent/config.json"), WithConfigFlagSet(fs, nil))
+	if err := loader(context.Background(), New()); err == nil {
+		t.Error("expect an error for the missing file")
+	}
+
+	file := writeConfigFile(t, `{"port": "abc"}`)
+	loader = NewConfigLoader(&cfg, WithConfigFile(file), WithConfigFlagSet(fs, nil))
+	if err := loader(context.Background(), New()); err == nil {
+		t.Error("expect an error for the invalid port")
+	}
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	loader = NewConfigLoader(&cfg, WithConfigFlagSet(fs, []string{"-unknown"}))
+	if err := loader(context.Background(), New()); err == nil {
+		t.Error("expect an error for the unknown flag")
+	}
+}
+
+func TestNewConfigLoader_Panics(t *testing.T) {
+	func() {
+		defer func() { _ = recover() }()
+		NewConfigLoader[testConfig](nil)
+		t.Error("expected panic for nil config")
+	}()
+
+	func() {
+		defer func() { _ = recover() }()
+		var i int
+		NewConfigLoader(&i)
+		t.Error("expected panic for non-struct config")
+	}()
+}
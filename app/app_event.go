@@ -0,0 +1,246 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// EventKind is the kind of the lifecycle event.
+type EventKind string
+
+const (
+	// EventStage is emitted when the app enters a new stage.
+	EventStage EventKind = "stage"
+
+	// EventModuleBegin and EventModuleEnd are emitted around
+	// Module.Init, Module.Start and Module.Stop, which is set in Event.Action.
+	EventModuleBegin EventKind = "module.begin"
+	EventModuleEnd   EventKind = "module.end"
+
+	// EventHookBegin and EventHookEnd are emitted around a hook.
+	EventHookBegin EventKind = "hook.begin"
+	EventHookEnd   EventKind = "hook.end"
+
+	// EventTaskStart is emitted each time a background task starts to run.
+	// Then either EventTaskExit or EventTaskPanic is emitted when it exits.
+	EventTaskStart   EventKind = "task.start"
+	EventTaskExit    EventKind = "task.exit"
+	EventTaskPanic   EventKind = "task.panic"
+	EventTaskRestart EventKind = "task.restart"
+
+	// EventLeaderAcquired and EventLeaderLost are emitted when the task
+	// started by GoLeader acquires and loses the leadership.
+	EventLeaderAcquired EventKind = "leader.acquired"
+	EventLeaderLost     EventKind = "leader.lost"
+
+	// EventSignal is emitted when the app receives a signal.
+	EventSignal EventKind = "signal"
+
+	// EventReload is emitted after the app has been reloaded.
+	EventReload EventKind = "reload"
+)
+
+// Event is a structured lifecycle event of the app.
+type Event struct {
+	Kind EventKind
+	Time time.Time
+
+	// Stage is the stage that the app has reached.
+	Stage Stage
+
+	// Name is the name of the module, the hook or the background task.
+	//
+	// For an unnamed hook, it is "#index".
+	Name string
+
+	// Action is one of "init", "start" and "stop" for the module events.
+	Action string
+
+	// Signal is the received signal for EventSignal.
+	Signal os.Signal
+
+	// Duration is the cost of the module call, the hook, the task run
+	// or the reload for the end events, or the delay before restarting
+	// for EventTaskRestart.
+	Duration time.Duration
+
+	// Err is the error returned by the module call, the hook or the task.
+	Err error
+
+	// Restarts is the number of the restarts of the background task.
+	Restarts int
+}
+
+// Observer is used to observe the lifecycle events of the app.
+//
+// Observe is called synchronously by the goroutine where the event occurs,
+// so it should be fast and must not block.
+type Observer interface {
+	Observe(Event)
+}
+
+// ObserverFunc is a function to observe the lifecycle events.
+type ObserverFunc func(Event)
+
+// Observe implements the interface Observer.
+func (f ObserverFunc) Observe(e Event) { f(e) }
+
+// Subscribe is a convenience function that calls DefaultApp.Subscribe.
+func Subscribe(o Observer) (unsubscribe func()) {
+	return DefaultApp.Subscribe(o)
+}
+
+// Subscribe registers an observer to receive the lifecycle events,
+// and returns a function to unregister it.
+//
+// It may be called at any time.
+func (a *App) Subscribe(o Observer) (unsubscribe func()) {
+	if o == nil {
+		panic("app: nil observer")
+	}
+
+	a.observers.mu.Lock()
+	defer a.observers.mu.Unlock()
+
+	a.observers.id++
+	id := a.observers.id
+	a.observers.list = append(a.observers.list, observer{id: id, Observer: o})
+
+	return func() {
+		a.observers.mu.Lock()
+		defer a.observers.mu.Unlock()
+		a.observers.list = slices.DeleteFunc(a.observers.list, func(o observer) bool { return o.id == id })
+	}
+}
+
+type observer struct {
+	id uint64
+	Observer
+}
+
+type observers struct {
+	mu   sync.RWMutex
+	id   uint64
+	list []observer
+}
+
+func (a *App) emit(e Event) {
+	a.observers.mu.RLock()
+	list := a.observers.list
+	a.observers.mu.RUnlock()
+
+	if len(list) == 0 {
+		return
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Stage == "" {
+		e.Stage = a.Stage()
+	}
+
+	for _, o := range list {
+		o.Observe(e)
+	}
+}
+
+// observeModule calls the module function and emits the module events.
+func (a *App) observeModule(m Module, action string, call func() error) error {
+	a.setModuleState(m.Name(), action, false, nil)
+	a.emit(Event{Kind: EventModuleBegin, Name: m.Name(), Action: action})
+
+	start := time.Now()
+	err := call()
+	a.setModuleState(m.Name(), action, true, err)
+
+	a.emit(Event{
+		Kind:     EventModuleEnd,
+		Name:     m.Name(),
+		Action:   action,
+		Duration: time.Since(start),
+		Err:      err,
+	})
+
+	return err
+}
+
+// NewLogObserver returns an observer that logs the lifecycle events by logger.
+//
+// The begin events are logged at the debug level, the events with an error
+// at the error level, and others at the info level.
+//
+// If logger is nil, use slog.Default() instead.
+func NewLogObserver(logger *slog.Logger) Observer {
+	return logObserver{logger: logger}
+}
+
+type logObserver struct {
+	logger *slog.Logger
+}
+
+func (o logObserver) Observe(e Event) {
+	logger := o.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	var level slog.Level
+	switch {
+	case e.Err != nil:
+		level = slog.LevelError
+
+	case e.Kind == EventModuleBegin, e.Kind == EventHookBegin, e.Kind == EventTaskStart:
+		level = slog.LevelDebug
+
+	default:
+		level = slog.LevelInfo
+	}
+
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, 8)
+	attrs = append(attrs, slog.String("event", string(e.Kind)), slog.String("stage", string(e.Stage)))
+
+	if e.Name != "" {
+		attrs = append(attrs, slog.String("name", e.Name))
+	}
+	if e.Action != "" {
+		attrs = append(attrs, slog.String("action", e.Action))
+	}
+	if e.Signal != nil {
+		attrs = append(attrs, slog.String("signal", e.Signal.String()))
+	}
+	if e.Duration > 0 {
+		attrs = append(attrs, slog.Duration("duration", e.Duration))
+	}
+	if e.Restarts > 0 {
+		attrs = append(attrs, slog.Int("restarts", e.Restarts))
+	}
+	if e.Err != nil {
+		attrs = append(attrs, slog.String("err", e.Err.Error()))
+	}
+
+	logger.LogAttrs(ctx, level, "app lifecycle event", attrs...)
+}
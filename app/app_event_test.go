@@ -0,0 +1,182 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSubscribe_Panics(t *testing.T) {
+	defer func() { _ = recover() }()
+	New().Subscribe(nil)
+	t.Error("expected panic")
+}
+
+func TestSubscribe(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetSignals()
+
+	var mu sync.Mutex
+	var events []string
+	unsubscribe := app.Subscribe(ObserverFunc(func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if e.Time.IsZero() {
+			t.Errorf("event %s has no time", e.Kind)
+		}
+
+		switch e.Kind {
+		case EventStage:
+			events = append(events, fmt.Sprintf("%s:%s", e.Kind, e.Stage))
+		case EventModuleEnd, EventModuleBegin:
+			events = append(events, fmt.Sprintf("%s:%s:%s:%v", e.Kind, e.Name, e.Action, e.Err))
+		default:
+			events = append(events, fmt.Sprintf("%s:%s:%v", e.Kind, e.Name, e.Err))
+		}
+	}))
+
+	app.Use(newTestModule("mod"))
+	app.OnNamed(StageStart, "task", func(ctx context.Context, app *App) error {
+		app.GoNamed("task", func(ctx context.Context) error {
+			panic("boom")
+		}, WithRestartPolicy(RestartOnFailure), WithMaxRestarts(1, 0), WithBackoff(time.Millisecond, time.Millisecond))
+		return nil
+	})
+
+	err := app.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	unsubscribe()
+	app.emit(Event{Kind: EventSignal})
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// The task events are interleaved with others, so check them separately.
+	var tasks, others []string
+	for _, e := range events {
+		if strings.HasPrefix(e, "task.") {
+			tasks = append(tasks, e)
+		} else {
+			others = append(others, e)
+		}
+	}
+
+	expectTasks := []string{
+		"task.start:task:<nil>",
+		"task.panic:task:panic: boom",
+		"task.restart:task:panic: boom",
+		"task.start:task:<nil>",
+		"task.panic:task:panic: boom",
+	}
+	if !slices.Equal(tasks, expectTasks) {
+		t.Errorf("unexpected task events:\n%s", strings.Join(tasks, "\n"))
+	}
+
+	expectOthers := []string{
+		"stage:init",
+		"module.begin:mod:init:<nil>",
+		"module.end:mod:init:<nil>",
+		"stage:start",
+		"hook.begin:task:<nil>",
+		"hook.end:task:<nil>",
+		"module.begin:mod:start:<nil>",
+		"module.end:mod:start:<nil>",
+		"stage:ready",
+		"stage:stopping",
+		"module.begin:mod:stop:<nil>",
+		"module.end:mod:stop:<nil>",
+		"stage:cleanup",
+		"stage:exited",
+	}
+	if !slices.Equal(others, expectOthers) {
+		t.Errorf("unexpected events:\n%s", strings.Join(others, "\n"))
+	}
+}
+
+func TestSubscribe_Signal(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetSignals(os.Interrupt)
+
+	signals := make(chan Event, 1)
+	app.Subscribe(ObserverFunc(func(e Event) {
+		if e.Kind == EventSignal {
+			signals <- e
+		}
+	}))
+
+	app.On(StageReady, func(ctx context.Context, app *App) error {
+		p, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			return err
+		}
+		return p.Signal(os.Interrupt)
+	})
+
+	if err := app.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case e := <-signals:
+		if e.Signal != os.Interrupt || e.Stage != StageReady {
+			t.Errorf("unexpected signal event: %+v", e)
+		}
+	default:
+		t.Error("expect a signal event")
+	}
+}
+
+func TestLogObserver(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	o := NewLogObserver(logger)
+	o.Observe(Event{Kind: EventModuleBegin, Stage: StageInit, Name: "db", Action: "init"})
+	o.Observe(Event{Kind: EventModuleEnd, Stage: StageInit, Name: "db", Action: "init", Duration: time.Second})
+	o.Observe(Event{Kind: EventHookEnd, Stage: StageStart, Name: "#0", Err: errors.New("fail")})
+	o.Observe(Event{Kind: EventSignal, Stage: StageReady, Signal: syscall.SIGTERM})
+
+	expect := `level=INFO msg="app lifecycle event" event=module.end stage=init name=db action=init duration=1s
+level=ERROR msg="app lifecycle event" event=hook.end stage=start name=#0 err=fail
+level=INFO msg="app lifecycle event" event=signal stage=ready signal=terminated
+`
+	if s := buf.String(); s != expect {
+		t.Errorf("expect logs:\n%s\nbut got:\n%s", expect, s)
+	}
+}
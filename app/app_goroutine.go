@@ -16,37 +16,108 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"time"
 )
 
+// RestartPolicy decides whether a background task is restarted after it exits.
+type RestartPolicy int
+
+const (
+	// RestartNever never restarts the task, which is the default.
+	//
+	// If the task returns a non-nil error, App will start shutdown.
+	RestartNever RestartPolicy = iota
+
+	// RestartOnFailure restarts the task only if it returns a non-nil error
+	// or panics.
+	RestartOnFailure
+
+	// RestartAlways restarts the task whenever it exits.
+	RestartAlways
+)
+
+// TaskOption is used to configure a background task started by Go or GoNamed.
+type TaskOption func(*taskOptions)
+
+type taskOptions struct {
+	policy      RestartPolicy
+	maxRestarts int
+	window      time.Duration
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+}
+
+// WithRestartPolicy returns a task option to set the restart policy.
+//
+// Each restart emits an EventTaskRestart event.
+//
+// Default: RestartNever
+func WithRestartPolicy(policy RestartPolicy) TaskOption {
+	return func(o *taskOptions) { o.policy = policy }
+}
+
+// WithMaxRestarts returns a task option to allow the task to be restarted
+// at most n times within window. When the task exits once more, App will
+// start shutdown.
+//
+// If n is not positive, the restarts are unlimited, which is the default.
+// If window is not positive, all the restarts during the app lifetime count.
+func WithMaxRestarts(n int, window time.Duration) TaskOption {
+	return func(o *taskOptions) { o.maxRestarts, o.window = n, window }
+}
+
+// WithBackoff returns a task option to set the exponential backoff schedule
+// before restarting the task.
+//
+// The delay starts with min and doubles after each consecutive restart,
+// but never exceeds max. A random jitter in [-delay/2, 0] is applied
+// to each delay. If the task has run for at least max, the delay is reset.
+//
+// Default: min=100ms, max=30s
+func WithBackoff(min, max time.Duration) TaskOption {
+	if min <= 0 || max < min {
+		panic("app: invalid task backoff")
+	}
+	return func(o *taskOptions) { o.minBackoff, o.maxBackoff = min, max }
+}
+
 // Go is a convenience function that calls DefaultApp.Go.
-func Go(fn func(ctx context.Context) error) {
-	GoNamed("", fn)
+func Go(fn func(ctx context.Context) error, opts ...TaskOption) {
+	GoNamed("", fn, opts...)
 }
 
 // GoNamed is a convenience function that calls DefaultApp.GoNamed.
-func GoNamed(name string, fn func(ctx context.Context) error) {
-	DefaultApp.GoNamed(name, fn)
+func GoNamed(name string, fn func(ctx context.Context) error, opts ...TaskOption) {
+	DefaultApp.GoNamed(name, fn, opts...)
 }
 
-// Go is short for App.GoNamed("", fn).
-func (a *App) Go(fn func(ctx context.Context) error) {
-	a.goNamed("", fn)
+// Go is short for App.GoNamed("", fn, opts...).
+func (a *App) Go(fn func(ctx context.Context) error, opts ...TaskOption) {
+	a.goNamed("", fn, opts)
 }
 
 // GoNamed starts a lifecycle-managed background task with the optional name.
 //
 // It can only be called after Run starts, usually inside Module.Start or hooks.
-// If fn returns a non-nil error while App is still running, App will start shutdown.
-func (a *App) GoNamed(name string, fn func(ctx context.Context) error) {
-	a.goNamed(name, fn)
+// If fn returns a non-nil error while App is still running, App will start
+// shutdown, unless the task is restarted according to the restart policy.
+func (a *App) GoNamed(name string, fn func(ctx context.Context) error, opts ...TaskOption) {
+	a.goNamed(name, fn, opts)
 }
 
-func (a *App) goNamed(name string, fn func(ctx context.Context) error) {
+func (a *App) goNamed(name string, fn func(ctx context.Context) error, opts []TaskOption) {
 	if fn == nil {
 		panic("app: nil background task func")
 	}
 
+	o := taskOptions{minBackoff: 100 * time.Millisecond, maxBackoff: 30 * time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	a.mu.Lock()
 
 	if a.state != stateRunning {
@@ -63,16 +134,12 @@ func (a *App) goNamed(name string, fn func(ctx context.Context) error) {
 
 	a.mu.Unlock()
 
+	task := a.addTask(name)
 	go func() {
 		defer a.wg.Done()
+		defer a.removeTask(task)
 
-		if err := saferun(runCtx, fn); err != nil {
-			// If the app is already shutting down, the task error is usually
-			// a consequence of cancellation and should not trigger another shutdown.
-			if runCtx.Err() != nil {
-				return
-			}
-
+		if err := a.runTask(runCtx, task, fn, o); err != nil {
 			wrapped := fmt.Errorf("app: background task %q: %w", name, err)
 
 			select {
@@ -83,15 +150,101 @@ func (a *App) goNamed(name string, fn func(ctx context.Context) error) {
 	}()
 }
 
+var errTaskExited = errors.New("task exited")
+
+// runTask runs fn until it exits without being restarted,
+// and returns the error that should trigger shutdown.
+func (a *App) runTask(ctx context.Context, task *taskStatus, fn func(context.Context) error, o taskOptions) error {
+	var restarts []time.Time
+	backoff := o.minBackoff
+	name := task.name
+
+	for {
+		if len(restarts) > 0 {
+			a.restartTask(task, len(restarts))
+		}
+		a.emit(Event{Kind: EventTaskStart, Name: name, Restarts: len(restarts)})
+
+		start := time.Now()
+		err := saferun(ctx, fn)
+
+		kind := EventTaskExit
+		if _, ok := err.(panicError); ok {
+			kind = EventTaskPanic
+		}
+		a.emit(Event{Kind: kind, Name: name, Duration: time.Since(start), Err: err, Restarts: len(restarts)})
+
+		// If the app is already shutting down, the task error is usually
+		// a consequence of cancellation and should not trigger another shutdown.
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		switch {
+		case o.policy == RestartAlways:
+		case o.policy == RestartOnFailure && err != nil:
+		default:
+			return err
+		}
+
+		if err == nil {
+			err = errTaskExited
+		}
+
+		now := time.Now()
+		if o.window > 0 {
+			expired := now.Add(-o.window)
+			for len(restarts) > 0 && restarts[0].Before(expired) {
+				restarts = restarts[1:]
+			}
+		}
+
+		if o.maxRestarts > 0 && len(restarts) >= o.maxRestarts {
+			if o.window > 0 {
+				return fmt.Errorf("restarted %d times within %s: %w", len(restarts), o.window, err)
+			}
+			return fmt.Errorf("restarted %d times: %w", len(restarts), err)
+		}
+		restarts = append(restarts, now)
+
+		if now.Sub(start) >= o.maxBackoff {
+			backoff = o.minBackoff
+		}
+
+		delay := backoff - rand.N(backoff/2+1)
+		backoff = min(backoff*2, o.maxBackoff)
+
+		a.emit(Event{Kind: EventTaskRestart, Name: name, Duration: delay, Err: err, Restarts: len(restarts)})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+
+		case <-timer.C:
+		}
+	}
+}
+
 func saferun(ctx context.Context, fn func(context.Context) error) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			if e, ok := r.(error); ok {
-				err = fmt.Errorf("panic: %w", e)
-			} else {
-				err = fmt.Errorf("panic: %v", r)
-			}
+			err = panicError{value: r}
 		}
 	}()
 	return fn(ctx)
 }
+
+type panicError struct {
+	value any
+}
+
+func (e panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+func (e panicError) Unwrap() error {
+	err, _ := e.value.(error)
+	return err
+}
@@ -123,3 +123,116 @@ func TestGo_Convenience(t *testing.T) {
 		t.Error("convenience Go not called")
 	}
 }
+
+func TestWithBackoff_Panics(t *testing.T) {
+	defer func() { _ = recover() }()
+	WithBackoff(time.Second, time.Millisecond)
+	t.Error("expected panic")
+}
+
+func TestGo_RestartOnFailure(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetSignals()
+
+	var runs atomic.Int32
+	app.On(StageStart, func(ctx context.Context, app *App) error {
+		app.GoNamed("consumer", func(ctx context.Context) error {
+			if runs.Add(1) < 3 {
+				panic("consumer failure")
+			}
+			<-ctx.Done()
+			return nil
+		}, WithRestartPolicy(RestartOnFailure), WithBackoff(time.Millisecond, 10*time.Millisecond))
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { time.Sleep(100 * time.Millisecond); cancel() }()
+	if err := app.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := runs.Load(); n != 3 {
+		t.Errorf("expect the task to run %d times, but got %d", 3, n)
+	}
+}
+
+func TestGo_RestartOnFailure_Success(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetSignals()
+
+	var runs atomic.Int32
+	app.On(StageStart, func(ctx context.Context, app *App) error {
+		app.Go(func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		}, WithRestartPolicy(RestartOnFailure), WithBackoff(time.Millisecond, time.Millisecond))
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { time.Sleep(50 * time.Millisecond); cancel() }()
+	if err := app.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := runs.Load(); n != 1 {
+		t.Errorf("expect the task to run %d time, but got %d", 1, n)
+	}
+}
+
+func TestGo_RestartAlways_MaxRestarts(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetSignals()
+
+	var runs atomic.Int32
+	app.On(StageStart, func(ctx context.Context, app *App) error {
+		app.GoNamed("ticker", func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+			WithRestartPolicy(RestartAlways),
+			WithMaxRestarts(3, time.Minute),
+			WithBackoff(time.Millisecond, 2*time.Millisecond),
+		)
+		return nil
+	})
+
+	err := app.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expect := `app: background task "ticker": restarted 3 times within 1m0s: task exited`
+	if s := err.Error(); s != expect {
+		t.Errorf("expect error %q, but got %q", expect, s)
+	}
+	if n := runs.Load(); n != 4 {
+		t.Errorf("expect the task to run %d times, but got %d", 4, n)
+	}
+}
+
+func TestGo_Restart_StopDuringBackoff(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetSignals()
+
+	app.On(StageStart, func(ctx context.Context, app *App) error {
+		app.Go(func(ctx context.Context) error {
+			return errors.New("failure")
+		}, WithRestartPolicy(RestartOnFailure), WithBackoff(time.Hour, time.Hour))
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { time.Sleep(50 * time.Millisecond); cancel() }()
+
+	start := time.Now()
+	if err := app.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cost := time.Since(start); cost > time.Second {
+		t.Errorf("the backoff should be interrupted by shutdown: %s", cost)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/xgfone/go-toolkit/internal/render"
+)
+
+// HealthCheck is a function to check whether a component is healthy.
+type HealthCheck func(ctx context.Context) error
+
+// HealthStatus is the result of the liveness or readiness checks.
+type HealthStatus struct {
+	Status string            `json:"status"`
+	Stage  Stage             `json:"stage,omitempty"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Healthy reports whether the status is healthy.
+func (s HealthStatus) Healthy() bool {
+	return s.Status == healthOK
+}
+
+const (
+	healthOK   = "ok"
+	healthFail = "fail"
+)
+
+type healthCheck struct {
+	id    uint64
+	name  string
+	check HealthCheck
+}
+
+type healthRegistry struct {
+	mu        sync.RWMutex
+	id        uint64
+	liveness  []healthCheck
+	readiness []healthCheck
+}
+
+func (r *healthRegistry) add(checks *[]healthCheck, name string, check HealthCheck) (remove func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.id++
+	id := r.id
+	*checks = append(*checks, healthCheck{id: id, name: name, check: check})
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		*checks = slices.DeleteFunc(*checks, func(c healthCheck) bool { return c.id == id })
+	}
+}
+
+func (r *healthRegistry) run(ctx context.Context, checks *[]healthCheck, status *HealthStatus) {
+	r.mu.RLock()
+	_checks := slices.Clone(*checks)
+	r.mu.RUnlock()
+
+	if len(_checks) > 0 && status.Checks == nil {
+		status.Checks = make(map[string]string, len(_checks))
+	}
+
+	for _, c := range _checks {
+		if err := c.check(ctx); err != nil {
+			status.Status = healthFail
+			status.Checks[c.name] = err.Error()
+		} else {
+			status.Checks[c.name] = healthOK
+		}
+	}
+}
+
+// AddLivenessCheck registers a named liveness check,
+// and returns a function to unregister it.
+//
+// It may be called at any time, for example, in Module.Init
+// or in a background task started by GoNamed.
+func (a *App) AddLivenessCheck(name string, check HealthCheck) (remove func()) {
+	if name == "" {
+		panic("app: empty health check name")
+	}
+	if check == nil {
+		panic("app: nil health check")
+	}
+	return a.health.add(&a.health.liveness, name, check)
+}
+
+// AddReadinessCheck registers a named readiness check,
+// and returns a function to unregister it.
+//
+// It may be called at any time, for example, in Module.Init
+// or in a background task started by GoNamed.
+func (a *App) AddReadinessCheck(name string, check HealthCheck) (remove func()) {
+	if name == "" {
+		panic("app: empty health check name")
+	}
+	if check == nil {
+		panic("app: nil health check")
+	}
+	return a.health.add(&a.health.readiness, name, check)
+}
+
+// Ready reports whether the app is ready, that's,
+// StageReady has completed and StageStopping has not begun.
+func (a *App) Ready() bool {
+	return a.ready.Load()
+}
+
+// Stage returns the lifecycle stage that the app has reached.
+//
+// Return "" if Run has not started.
+func (a *App) Stage() Stage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stage
+}
+
+// CheckLiveness runs all the liveness checks.
+func (a *App) CheckLiveness(ctx context.Context) HealthStatus {
+	status := HealthStatus{Status: healthOK}
+	a.health.run(ctx, &a.health.liveness, &status)
+	return status
+}
+
+// CheckReadiness runs all the liveness and readiness checks.
+//
+// The status is not healthy if the app is not ready.
+func (a *App) CheckReadiness(ctx context.Context) HealthStatus {
+	status := HealthStatus{Status: healthOK}
+	if !a.Ready() {
+		status.Status = healthFail
+		status.Stage = a.Stage()
+	}
+
+	a.health.run(ctx, &a.health.liveness, &status)
+	a.health.run(ctx, &a.health.readiness, &status)
+	return status
+}
+
+// HealthHandler returns a http handler to serve the liveness and readiness
+// checks as JSON, which responds the request whose path ends with "/healthz"
+// by CheckLiveness and "/readyz" by CheckReadiness, and 404 for others.
+//
+// The status code is 200 if healthy, or 503.
+func (a *App) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var status HealthStatus
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/healthz"):
+			status = a.CheckLiveness(r.Context())
+
+		case strings.HasSuffix(r.URL.Path, "/readyz"):
+			status = a.CheckReadiness(r.Context())
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		code := http.StatusOK
+		if !status.Healthy() {
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		_ = render.JSON(w, code, status)
+	})
+}
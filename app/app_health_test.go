@@ -0,0 +1,98 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func serveHealth(app *App, path string) (int, string) {
+	rec := httptest.NewRecorder()
+	app.HealthHandler().ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+	return rec.Code, strings.TrimSpace(rec.Body.String())
+}
+
+func TestHealthHandler(t *testing.T) {
+	app := New()
+
+	if code, body := serveHealth(app, "/healthz"); code != 200 || body != `{"status":"ok"}` {
+		t.Errorf("unexpected liveness: %d %s", code, body)
+	}
+	if code, _ := serveHealth(app, "/readyz"); code != 503 {
+		t.Errorf("expect status code %d before ready, but got %d", 503, code)
+	}
+	if code, _ := serveHealth(app, "/unknown"); code != 404 {
+		t.Errorf("expect status code %d, but got %d", 404, code)
+	}
+
+	var dbErr error
+	app.AddLivenessCheck("db", func(context.Context) error { return dbErr })
+	remove := app.AddReadinessCheck("cache", func(context.Context) error { return nil })
+	app.ready.Store(true)
+
+	if code, body := serveHealth(app, "/readyz"); code != 200 || body != `{"status":"ok","checks":{"cache":"ok","db":"ok"}}` {
+		t.Errorf("unexpected readiness: %d %s", code, body)
+	}
+
+	dbErr = errors.New("down")
+	remove()
+	if code, body := serveHealth(app, "/health/readyz"); code != 503 || body != `{"status":"fail","checks":{"db":"down"}}` {
+		t.Errorf("unexpected readiness: %d %s", code, body)
+	}
+}
+
+func TestHealth_Lifecycle(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetSignals()
+
+	var readyAtReady, readyAtStopping bool
+	app.On(StageReady, func(ctx context.Context, app *App) error {
+		readyAtReady = app.Ready()
+		return nil
+	})
+	app.On(StageStopping, func(ctx context.Context, app *App) error {
+		readyAtStopping = app.Ready()
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		if code, _ := serveHealth(app, "/readyz"); code != 200 {
+			t.Errorf("expect status code %d while running, but got %d", 200, code)
+		}
+		cancel()
+	}()
+
+	if err := app.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if readyAtReady {
+		t.Error("app should not be ready during StageReady")
+	}
+	if readyAtStopping {
+		t.Error("app should not be ready during StageStopping")
+	}
+	if code, body := serveHealth(app, "/readyz"); code != 503 || body != `{"status":"fail","stage":"exited"}` {
+		t.Errorf("unexpected readiness after exit: %d %s", code, body)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"iter"
 	"slices"
+	"time"
 
 	"github.com/xgfone/go-toolkit/iox"
 )
@@ -104,8 +105,10 @@ func (a *App) runHooks(ctx context.Context, stage Stage) error {
 		seq2 = slices.All(hooks)
 	}
 
+	a.emit(Event{Kind: EventStage, Stage: stage})
+
 	for i, hook := range seq2 {
-		if err := hook.hook(ctx, a); err != nil {
+		if err := a.runHook(ctx, stage, hook, i); err != nil {
 			wrapped := fmt.Errorf("app: hook %s: %w", hookLabel(stage, hook.name, i), err)
 
 			// During shutdown stages, continue executing remaining hooks.
@@ -121,6 +124,38 @@ func (a *App) runHooks(ctx context.Context, stage Stage) error {
 	return errors.Join(errs...)
 }
 
+func (a *App) runHook(ctx context.Context, stage Stage, hook namedHook, index int) error {
+	name := hook.name
+	if name == "" {
+		name = fmt.Sprintf("#%d", index)
+	}
+
+	a.emit(Event{Kind: EventHookBegin, Stage: stage, Name: name})
+
+	start := time.Now()
+	var err error
+	switch stage {
+	case StageInit, StageStart, StageReady:
+		err = a.callContext(ctx, 0, func(ctx context.Context) error { return hook.hook(ctx, a) }, nil)
+
+	default:
+		// The shutdown hooks, such as closing the resources, are always waited for,
+		// so that the later ones never run concurrently with the unfinished one.
+		// They still get the deadline through ctx.
+		err = hook.hook(ctx, a)
+	}
+
+	a.emit(Event{
+		Kind:     EventHookEnd,
+		Stage:    stage,
+		Name:     name,
+		Duration: time.Since(start),
+		Err:      err,
+	})
+
+	return err
+}
+
 func hookLabel(stage Stage, name string, index int) string {
 	if name != "" {
 		return fmt.Sprintf("%q at stage %q", name, stage)
@@ -155,6 +155,34 @@ func TestHook_StoppingError_Continues(t *testing.T) {
 	}
 }
 
+func TestHook_StoppingWaitedAfterTimeout(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetShutdownTimeout(20 * time.Millisecond)
+	app.SetSignals()
+
+	var stopped, stoppedBeforeCleanup atomic.Bool
+	app.On(StageStopping, func(ctx context.Context, app *App) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond) // Simulate closing the resources.
+		stopped.Store(true)
+		return ctx.Err()
+	})
+	app.Cleanup(func() error {
+		stoppedBeforeCleanup.Store(stopped.Load())
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { time.Sleep(50 * time.Millisecond); cancel() }()
+	if err := app.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect a deadline error, but got %v", err)
+	}
+	if !stoppedBeforeCleanup.Load() {
+		t.Error("the cleanup hook should run after the stopping hook finishes")
+	}
+}
+
 func TestHook_ExitedError_Continues(t *testing.T) {
 	app := New()
 	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
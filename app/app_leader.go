@@ -0,0 +1,316 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xgfone/go-toolkit/internal/flock"
+)
+
+// Elector is used to elect the leader among the replicas.
+//
+// An Elector represents a candidate, which is campaigned by one task at a time.
+type Elector interface {
+	// Campaign blocks until the candidate becomes the leader or ctx is done,
+	// and returns a channel which is closed when the leadership is lost.
+	Campaign(ctx context.Context) (lost <-chan struct{}, err error)
+
+	// Resign gives up the leadership if it is the leader.
+	Resign(ctx context.Context) error
+}
+
+// GoLeader is a convenience function that calls DefaultApp.GoLeader.
+func GoLeader(name string, elector Elector, fn func(ctx context.Context) error, opts ...TaskOption) {
+	DefaultApp.GoLeader(name, elector, fn, opts...)
+}
+
+// GoLeader starts a lifecycle-managed background task like GoNamed,
+// but fn only runs while the elector holds the leadership.
+//
+// The context of fn is canceled when the leadership is lost, then it campaigns
+// again and reruns fn once the leadership is re-acquired. If fn returns
+// while still being the leader, the leadership is resigned and the task exits
+// with the returned error, which is handled like GoNamed.
+//
+// Since another replica may become the leader as soon as the leadership
+// is lost, fn should return promptly when its context is canceled.
+func (a *App) GoLeader(name string, elector Elector, fn func(ctx context.Context) error, opts ...TaskOption) {
+	if elector == nil {
+		panic("app: nil elector")
+	}
+	if fn == nil {
+		panic("app: nil background task func")
+	}
+
+	a.goNamed(name, func(ctx context.Context) error {
+		for {
+			lost, err := elector.Campaign(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("campaign: %w", err)
+			}
+
+			a.emit(Event{Kind: EventLeaderAcquired, Name: name})
+			isLost, err := runLeader(ctx, elector, lost, fn)
+			if isLost {
+				a.emit(Event{Kind: EventLeaderLost, Name: name})
+			}
+
+			switch {
+			case ctx.Err() != nil:
+				return nil
+			case !isLost:
+				return err
+			}
+		}
+	}, opts)
+}
+
+// runLeader runs fn until it returns or the leadership is lost,
+// then resigns the leadership.
+func runLeader(ctx context.Context, elector Elector, lost <-chan struct{},
+	fn func(context.Context) error) (isLost bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	done := make(chan struct{})
+	watched := make(chan bool, 1)
+	go func() {
+		select {
+		case <-lost:
+			cancel()
+			watched <- true
+		case <-done:
+			watched <- false
+		}
+	}()
+
+	defer func() {
+		rctx, rcancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer rcancel()
+		if e := elector.Resign(rctx); e != nil {
+			err = errors.Join(err, fmt.Errorf("resign: %w", e))
+		}
+	}()
+
+	defer func() {
+		close(done)
+		isLost = <-watched
+		cancel()
+	}()
+
+	err = fn(ctx)
+	return
+}
+
+// NewFileElector returns an elector based on the advisory lock of the file,
+// which may be on the shared storage, such as NFS supporting the file lock.
+//
+// It tries to lock the file at the interval until it succeeds. As the leader,
+// it checks the file at the interval, and the leadership is lost if the file
+// is removed or replaced.
+func NewFileElector(path string, interval time.Duration) Elector {
+	if path == "" {
+		panic("app: the elector file path must not be empty")
+	}
+	if interval <= 0 {
+		panic("app: the elector interval must be greater than 0")
+	}
+	return &fileElector{path: path, interval: interval}
+}
+
+type fileElector struct {
+	path     string
+	interval time.Duration
+
+	mu   sync.Mutex
+	file *os.File
+	stop chan struct{}
+}
+
+func (e *fileElector) Campaign(ctx context.Context) (<-chan struct{}, error) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		file, err := e.tryLock()
+		switch {
+		case err == nil:
+			return e.lead(file), nil
+		case !errors.Is(err, flock.ErrLocked):
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *fileElector) tryLock() (*os.File, error) {
+	file, err := os.OpenFile(e.path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = flock.TryLock(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	// Record the leader for troubleshooting only.
+	hostname, _ := os.Hostname()
+	_ = file.Truncate(0)
+	_, _ = file.WriteAt([]byte(hostname+" "+strconv.Itoa(os.Getpid())+"\n"), 0)
+	return file, nil
+}
+
+func (e *fileElector) lead(file *os.File) <-chan struct{} {
+	lost := make(chan struct{})
+	stop := make(chan struct{})
+
+	e.mu.Lock()
+	e.file, e.stop = file, stop
+	e.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if !e.isLocked(file) {
+					close(lost)
+					return
+				}
+			}
+		}
+	}()
+
+	return lost
+}
+
+// isLocked reports whether the path still refers to the locked file.
+func (e *fileElector) isLocked(file *os.File) bool {
+	fi1, err := os.Stat(e.path)
+	if err != nil {
+		return false
+	}
+
+	fi2, err := file.Stat()
+	return err == nil && os.SameFile(fi1, fi2)
+}
+
+func (e *fileElector) Resign(context.Context) (err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.file != nil {
+		close(e.stop)
+		err = e.file.Close()
+		e.file, e.stop = nil, nil
+	}
+	return
+}
+
+// NewMemoryElection returns a new in-memory election, whose electors compete
+// for the leadership in the same process, which is mainly used in tests.
+func NewMemoryElection() *MemoryElection {
+	return &MemoryElection{changed: make(chan struct{})}
+}
+
+// MemoryElection is an in-memory election among its electors.
+type MemoryElection struct {
+	mu      sync.Mutex
+	leader  *memoryElector
+	changed chan struct{}
+}
+
+// NewElector returns a new elector as a candidate of the election.
+func (e *MemoryElection) NewElector() Elector {
+	return &memoryElector{election: e}
+}
+
+// HasLeader reports whether the election has a leader.
+func (e *MemoryElection) HasLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader != nil
+}
+
+// Revoke revokes the leadership of the current leader if exists,
+// then all the electors campaign again.
+func (e *MemoryElection) Revoke() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.resignLocked(e.leader)
+}
+
+func (e *MemoryElection) resignLocked(m *memoryElector) {
+	if m == nil || e.leader != m {
+		return
+	}
+
+	close(m.lost)
+	e.leader = nil
+	close(e.changed)
+	e.changed = make(chan struct{})
+}
+
+type memoryElector struct {
+	election *MemoryElection
+	lost     chan struct{}
+}
+
+func (m *memoryElector) Campaign(ctx context.Context) (<-chan struct{}, error) {
+	e := m.election
+	for {
+		e.mu.Lock()
+		if e.leader == nil {
+			e.leader = m
+			m.lost = make(chan struct{})
+			e.mu.Unlock()
+			return m.lost, nil
+		}
+		changed := e.changed
+		e.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (m *memoryElector) Resign(context.Context) error {
+	m.election.mu.Lock()
+	defer m.election.mu.Unlock()
+	m.election.resignLocked(m)
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGoLeader(t *testing.T) {
+	election := NewMemoryElection()
+
+	app := New()
+	app.SetSignals()
+	app.SetConfigLoader(func(context.Context, *App) error { return nil })
+
+	var runs atomic.Int32
+	started := make(chan struct{}, 4)
+	task := func(ctx context.Context) error {
+		runs.Add(1)
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	var acquired, lost atomic.Int32
+	app.Subscribe(ObserverFunc(func(e Event) {
+		switch e.Kind {
+		case EventLeaderAcquired:
+			acquired.Add(1)
+		case EventLeaderLost:
+			lost.Add(1)
+		}
+	}))
+
+	app.On(StageReady, func(ctx context.Context, app *App) error {
+		app.GoLeader("leader1", election.NewElector(), task)
+		app.GoLeader("leader2", election.NewElector(), task)
+		return nil
+	})
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- app.Run(context.Background()) }()
+
+	waitStarted := func() {
+		select {
+		case <-started:
+		case <-time.After(time.Second * 3):
+			t.Fatal("timeout to wait for the leader task")
+		}
+	}
+
+	waitStarted()
+	time.Sleep(time.Millisecond * 20)
+	if n := runs.Load(); n != 1 {
+		t.Errorf("expect only 1 leader, but got %d", n)
+	}
+
+	election.Revoke()
+	waitStarted()
+
+	app.Stop()
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+
+	if n := runs.Load(); n != 2 {
+		t.Errorf("expect the task runs 2 times, but got %d", n)
+	}
+	if n := acquired.Load(); n != 2 {
+		t.Errorf("expect 2 acquired events, but got %d", n)
+	}
+	if n := lost.Load(); n != 1 {
+		t.Errorf("expect 1 lost event, but got %d", n)
+	}
+	if election.HasLeader() {
+		t.Error("expect the leadership is resigned after stopping, but not")
+	}
+}
+
+func TestGoLeader_Return(t *testing.T) {
+	election := NewMemoryElection()
+
+	app := New()
+	app.SetSignals()
+	app.SetConfigLoader(func(context.Context, *App) error { return nil })
+
+	errFail := errors.New("fail")
+	app.On(StageReady, func(ctx context.Context, app *App) error {
+		app.GoLeader("leader", election.NewElector(), func(ctx context.Context) error {
+			return errFail
+		})
+		return nil
+	})
+
+	if err := app.Run(context.Background()); !errors.Is(err, errFail) {
+		t.Errorf("expect error %v, but got %v", errFail, err)
+	}
+	if election.HasLeader() {
+		t.Error("expect the leadership is resigned, but not")
+	}
+}
+
+func TestFileElector(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader.lock")
+	e1 := NewFileElector(path, time.Millisecond*10)
+	e2 := NewFileElector(path, time.Millisecond*10)
+
+	lost, err := e1.Campaign(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	if _, err := e2.Campaign(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect error %v, but got %v", context.DeadlineExceeded, err)
+	}
+
+	// Removing the lock file loses the leadership.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-lost:
+	case <-time.After(time.Second):
+		t.Error("expect the leadership is lost, but not")
+	}
+
+	if err := e1.Resign(context.Background()); err != nil {
+		t.Errorf("fail to resign: %v", err)
+	}
+
+	if _, err := e2.Campaign(context.Background()); err != nil {
+		t.Errorf("expect to be the leader, but got %v", err)
+	}
+	_ = e2.Resign(context.Background())
+}
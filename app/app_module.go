@@ -16,15 +16,19 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/xgfone/go-toolkit/internal/priority"
 )
 
 // Module represents a lifecycle-managed component.
 //
-// Init and Start are executed in registration order.
-// Stop is executed in reverse registration order.
+// Init and Start are executed in registration order, which may be adjusted
+// by the optional interfaces interface{ Priority() int } and Dependent.
+// Stop is executed in the reverse order.
 type Module interface {
 	Name() string
 	Init(ctx context.Context, app *App) error
@@ -32,6 +36,16 @@ type Module interface {
 	Stop(ctx context.Context, app *App) error
 }
 
+// Dependent is an optional interface that a Module may implement
+// to declare the names of the modules that it depends on.
+//
+// A module is always initialized and started after all of its dependencies,
+// and is stopped before them. If the dependencies contain a cycle or
+// reference an unregistered module, Run fails before initializing any module.
+type Dependent interface {
+	DependsOn() []string
+}
+
 // Use registers lifecycle modules for the default app.
 //
 // It must be called before Run.
@@ -61,6 +75,225 @@ func (a *App) Use(mods ...Module) {
 	}
 }
 
+// SetModuleConcurrency sets the maximum number of modules
+// whose Init or Start may run concurrently.
+//
+// If workers is greater than 1, a module is initialized or started as soon as
+// all of its dependencies declared by Dependent have finished, so the modules
+// without ordering constraints between them run in parallel. In this mode,
+// the priority only decides which ready module is scheduled first.
+// If one or more modules fail, no more modules are scheduled, the running ones
+// are waited for, and all the errors are joined.
+//
+// Default: 1, that's, Init and Start are executed one by one.
+//
+// It must be called before Run.
+func (a *App) SetModuleConcurrency(workers int) {
+	if workers <= 0 {
+		panic("app: module concurrency must be positive")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.mustBeNewLocked("SetModuleConcurrency")
+	a.moduleWorkers = workers
+}
+
+// runModules calls call for each module in graph, and calls done
+// in the order that the modules have finished successfully.
+func (a *App) runModules(graph moduleGraph, call func(Module) error, done func(Module)) error {
+	a.mu.Lock()
+	workers := a.moduleWorkers
+	a.mu.Unlock()
+
+	if done == nil {
+		done = func(Module) {}
+	}
+
+	if workers <= 1 {
+		for _, m := range graph.modules {
+			if err := call(m); err != nil {
+				return err
+			}
+			done(m)
+		}
+		return nil
+	}
+
+	total := len(graph.modules)
+	pending := make([]int, total)
+	dependents := make([][]int, total)
+	for i, deps := range graph.deps {
+		pending[i] = len(deps)
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], i)
+		}
+	}
+
+	var ready []int
+	for i := range total {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	type result struct {
+		index int
+		err   error
+	}
+
+	var errs []error
+	var running int
+	results := make(chan result, total)
+	for {
+		for len(errs) == 0 && len(ready) > 0 && running < workers {
+			index := ready[0]
+			ready = ready[1:]
+			running++
+
+			go func() {
+				results <- result{index: index, err: call(graph.modules[index])}
+			}()
+		}
+
+		if running == 0 {
+			break
+		}
+
+		r := <-results
+		running--
+
+		if r.err != nil {
+			errs = append(errs, r.err)
+			continue
+		}
+
+		done(graph.modules[r.index])
+		for _, i := range dependents[r.index] {
+			if pending[i]--; pending[i] == 0 {
+				ready = append(ready, i)
+			}
+		}
+		slices.Sort(ready)
+	}
+
+	return errors.Join(errs...)
+}
+
+// moduleGraph is the resolved module dependency graph.
+type moduleGraph struct {
+	// modules is sorted in the topological order.
+	modules []Module
+
+	// deps[i] contains the indexes of the modules that modules[i] depends on.
+	deps [][]int
+}
+
+// resolveModules sorts mods by priority first, then reorders them so that
+// every module comes after all of its dependencies. Among the modules whose
+// dependencies are satisfied, the one that comes first by priority wins,
+// so the order is unchanged when no module declares dependencies.
+func resolveModules(mods []Module) (graph moduleGraph, err error) {
+	sortModules(mods)
+
+	indexes := make(map[string][]int, len(mods))
+	for i, m := range mods {
+		indexes[m.Name()] = append(indexes[m.Name()], i)
+	}
+
+	deps := make([][]int, len(mods))
+	for i, m := range mods {
+		d, ok := m.(Dependent)
+		if !ok {
+			continue
+		}
+
+		for _, name := range d.DependsOn() {
+			idxs, ok := indexes[name]
+			if !ok {
+				return graph, fmt.Errorf("app: module %q depends on missing module %q", m.Name(), name)
+			}
+			deps[i] = append(deps[i], idxs...)
+		}
+	}
+
+	order := make([]int, 0, len(mods))
+	position := make([]int, len(mods))
+	visited := make([]bool, len(mods))
+
+	for len(order) < len(mods) {
+		next := -1
+		for i := range mods {
+			if !visited[i] && allVisited(visited, deps[i]) {
+				next = i
+				break
+			}
+		}
+
+		if next < 0 {
+			return graph, fmt.Errorf("app: module dependency cycle: %s", findCycle(mods, deps, visited))
+		}
+
+		visited[next] = true
+		position[next] = len(order)
+		order = append(order, next)
+	}
+
+	graph.modules = make([]Module, len(mods))
+	graph.deps = make([][]int, len(mods))
+	for i, index := range order {
+		graph.modules[i] = mods[index]
+		for _, dep := range deps[index] {
+			graph.deps[i] = append(graph.deps[i], position[dep])
+		}
+	}
+
+	return
+}
+
+func allVisited(visited []bool, indexes []int) bool {
+	for _, i := range indexes {
+		if !visited[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// findCycle returns a description of a dependency cycle
+// among the modules that have not been visited.
+func findCycle(mods []Module, deps [][]int, visited []bool) string {
+	// Every unvisited module has at least one unvisited dependency,
+	// so walking along them must eventually come back to a seen module.
+	seen := make(map[int]int, len(mods))
+	var path []int
+
+	current := slices.Index(visited, false)
+	for {
+		if start, ok := seen[current]; ok {
+			path = append(path[start:], current)
+			break
+		}
+
+		seen[current] = len(path)
+		path = append(path, current)
+
+		for _, dep := range deps[current] {
+			if !visited[dep] {
+				current = dep
+				break
+			}
+		}
+	}
+
+	names := make([]string, len(path))
+	for i, index := range path {
+		names[i] = mods[index].Name()
+	}
+	return strings.Join(names, " -> ")
+}
+
 func sortModules(mods []Module) {
 	slices.SortStableFunc(mods, func(a, b Module) int {
 		return priority.Get(b) - priority.Get(a)
@@ -18,6 +18,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -247,3 +250,236 @@ func newPriorityModule(priority int, mod Module) _PriorityModule {
 func (p _PriorityModule) Priority() int {
 	return p.priority
 }
+
+type _DependentModule struct {
+	deps []string
+	Module
+}
+
+func newDependentModule(mod Module, deps ...string) _DependentModule {
+	return _DependentModule{deps: deps, Module: mod}
+}
+
+func (m _DependentModule) DependsOn() []string {
+	return m.deps
+}
+
+func moduleNames(mods []Module) []string {
+	names := make([]string, len(mods))
+	for i, m := range mods {
+		names[i] = m.Name()
+	}
+	return names
+}
+
+func TestResolveModules(t *testing.T) {
+	graph, err := resolveModules([]Module{
+		newDependentModule(newTestModule("http"), "db", "cache"),
+		newDependentModule(newTestModule("cache"), "db"),
+		newTestModule("db"),
+		newTestModule("other"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expects := []string{"db", "cache", "http", "other"}
+	if names := moduleNames(graph.modules); !slices.Equal(names, expects) {
+		t.Errorf("expect modules %v, but got %v", expects, names)
+	}
+
+	if deps := graph.deps[2]; !slices.Equal(deps, []int{0, 1}) {
+		t.Errorf("expect deps %v, but got %v", []int{0, 1}, deps)
+	}
+}
+
+func TestResolveModules_KeepPriorityOrder(t *testing.T) {
+	graph, err := resolveModules([]Module{
+		newPriorityModule(1, newTestModule("m1")),
+		newPriorityModule(3, newTestModule("m3")),
+		newPriorityModule(2, newTestModule("m2")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expects := []string{"m3", "m2", "m1"}
+	if names := moduleNames(graph.modules); !slices.Equal(names, expects) {
+		t.Errorf("expect modules %v, but got %v", expects, names)
+	}
+}
+
+func TestResolveModules_Missing(t *testing.T) {
+	_, err := resolveModules([]Module{
+		newDependentModule(newTestModule("http"), "db"),
+	})
+
+	expect := `app: module "http" depends on missing module "db"`
+	if err == nil || err.Error() != expect {
+		t.Errorf("expect error %q, but got %v", expect, err)
+	}
+}
+
+func TestResolveModules_Cycle(t *testing.T) {
+	_, err := resolveModules([]Module{
+		newTestModule("db"),
+		newDependentModule(newTestModule("a"), "b"),
+		newDependentModule(newTestModule("b"), "c", "db"),
+		newDependentModule(newTestModule("c"), "a"),
+	})
+
+	expect := "app: module dependency cycle: a -> b -> c -> a"
+	if err == nil || err.Error() != expect {
+		t.Errorf("expect error %q, but got %v", expect, err)
+	}
+}
+
+func TestModule_DependencyOrder(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetSignals()
+
+	var calls []string
+	newModule := func(name string, deps ...string) Module {
+		m := newTestModule(name)
+		m.init = func(context.Context, *App) error { calls = append(calls, "init:"+name); return nil }
+		m.start = func(context.Context, *App) error { calls = append(calls, "start:"+name); return nil }
+		m.stop = func(context.Context, *App) error { calls = append(calls, "stop:"+name); return nil }
+		return newDependentModule(m, deps...)
+	}
+
+	app.Use(newModule("http", "db"), newModule("db"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { time.Sleep(50 * time.Millisecond); cancel() }()
+	if err := app.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expects := []string{"init:db", "init:http", "start:db", "start:http", "stop:http", "stop:db"}
+	if !slices.Equal(calls, expects) {
+		t.Errorf("expect calls %v, but got %v", expects, calls)
+	}
+}
+
+func TestModule_DependencyError(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetSignals()
+
+	mod := newTestModule("http")
+	app.Use(newDependentModule(mod, "db"))
+
+	if err := app.Run(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if atomic.LoadInt32(mod.initCalled) != 0 {
+		t.Error("init should NOT be called when dependencies are missing")
+	}
+	app.Wait()
+}
+
+func TestSetModuleConcurrency_Panics(t *testing.T) {
+	defer func() { _ = recover() }()
+	New().SetModuleConcurrency(0)
+	t.Error("expected panic")
+}
+
+func TestModule_Concurrency(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetModuleConcurrency(4)
+	app.SetSignals()
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) {
+		mu.Lock()
+		calls = append(calls, call)
+		mu.Unlock()
+	}
+
+	var running, maxRunning atomic.Int32
+	newModule := func(name string, deps ...string) Module {
+		m := newTestModule(name)
+		m.init = func(context.Context, *App) error {
+			if n := running.Add(1); n > maxRunning.Load() {
+				maxRunning.Store(n)
+			}
+			time.Sleep(50 * time.Millisecond)
+			running.Add(-1)
+			record("init:" + name)
+			return nil
+		}
+		return newDependentModule(m, deps...)
+	}
+
+	app.Use(newModule("m1"), newModule("m2"), newModule("m3"), newModule("m4", "m1", "m2", "m3"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { time.Sleep(200 * time.Millisecond); cancel() }()
+
+	start := time.Now()
+	if err := app.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if cost := time.Since(start); cost > 500*time.Millisecond {
+		t.Errorf("modules are not initialized concurrently: %s", cost)
+	}
+	if n := maxRunning.Load(); n != 3 {
+		t.Errorf("expect 3 modules to be initialized concurrently, but got %d", n)
+	}
+	if len(calls) != 4 || calls[3] != "init:m4" {
+		t.Errorf("expect m4 to be initialized last, but got %v", calls)
+	}
+}
+
+func TestModule_ConcurrencyRollback(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetModuleConcurrency(2)
+	app.SetSignals()
+
+	var mu sync.Mutex
+	var stops []string
+	newModule := func(name string, delay time.Duration, deps ...string) *testModule {
+		m := newTestModule(name)
+		m.init = func(context.Context, *App) error {
+			time.Sleep(delay)
+			atomic.AddInt32(m.initCalled, 1)
+			if strings.HasPrefix(name, "err") {
+				return errors.New("init fail")
+			}
+			return nil
+		}
+		m.stop = func(context.Context, *App) error {
+			mu.Lock()
+			stops = append(stops, name)
+			mu.Unlock()
+			return nil
+		}
+		return m
+	}
+
+	m1 := newModule("m1", 0)
+	m2 := newModule("m2", 0, "m1")
+	e1 := newModule("err1", 50*time.Millisecond)
+	e2 := newModule("err2", 50*time.Millisecond, "m1")
+	m3 := newModule("m3", 0, "err1")
+	app.Use(m1, newDependentModule(m2, "m1"), e1, newDependentModule(e2, "m1"), newDependentModule(m3, "err1"))
+
+	err := app.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if s := err.Error(); !strings.Contains(s, `"err1"`) || !strings.Contains(s, `"err2"`) {
+		t.Errorf("expect the joined errors of err1 and err2, but got %q", s)
+	}
+	if atomic.LoadInt32(m3.initCalled) != 0 {
+		t.Error("m3 should NOT be initialized")
+	}
+	if !slices.Equal(stops, []string{"m2", "m1"}) {
+		t.Errorf("expect to stop [m2 m1], but got %v", stops)
+	}
+}
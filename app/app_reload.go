@@ -0,0 +1,157 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+)
+
+var errNotReady = errors.New("app: not ready")
+
+// Reloader is an optional interface that a Module may implement
+// to be notified after the config has been reloaded.
+//
+// If Reload fails, the reloaded config is not rolled back,
+// so the module should keep working with its previous state.
+type Reloader interface {
+	Reload(ctx context.Context, app *App) error
+}
+
+// SetReloadSignals sets signals that trigger Reload.
+//
+// Passing no signals means App will not reload on OS signals.
+// The reload triggered by the signal runs in the background, which is
+// limited by the shutdown timeout, and the signals received during it
+// are merged into one pending reload.
+//
+// It must be called before Run.
+func (a *App) SetReloadSignals(signals ...os.Signal) {
+	for _, sig := range signals {
+		if sig == nil {
+			panic("app: nil signal")
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.mustBeNewLocked("SetReloadSignals")
+	a.reloadSignals = slices.Clone(signals)
+}
+
+// Reload re-runs the config loader, then calls Reload of the modules
+// implementing the interface Reloader in the dependency order.
+//
+// If the config loader fails, no module is notified, and the previous config
+// is kept as long as the loader does not modify it on failure, such as the
+// one returned by NewConfigLoader. If some modules fail, the remaining modules
+// are still notified and all the errors are joined, but the reloaded config
+// stays in effect since it has been applied by the loader.
+// Either way, the error is reported by the returned error, an EventReload
+// event and the error log, but the app keeps running.
+//
+// It can only be called while the app is ready, and the concurrent calls
+// are executed one by one. When the app starts to shut down, the context
+// of the in-flight reload is canceled, and the shutdown waits for it to
+// return before running the stopping hooks.
+func (a *App) Reload(ctx context.Context) (err error) {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	if !a.Ready() {
+		return errNotReady
+	}
+
+	a.mu.Lock()
+	loader := a.configLoader
+	modules := a.sorted
+	runCtx := a.runCtx
+	a.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if runCtx != nil {
+		defer context.AfterFunc(runCtx, cancel)()
+	}
+
+	start := time.Now()
+	defer func() {
+		a.emit(Event{Kind: EventReload, Duration: time.Since(start), Err: err})
+		if err != nil {
+			slog.Error("fail to reload the app", "err", err)
+		}
+	}()
+
+	if err = loader(ctx, a); err != nil {
+		return fmt.Errorf("app: reload config: %w", err)
+	}
+
+	var errs []error
+	for _, m := range modules {
+		if r, ok := m.(Reloader); ok {
+			if e := r.Reload(ctx, a); e != nil {
+				errs = append(errs, fmt.Errorf("app: reload module %q: %w", m.Name(), e))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// reloadLoop runs the reloads triggered by the signals until ctx is done.
+func (a *App) reloadLoop(ctx context.Context, reloadCh <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-reloadCh:
+			reloadCtx, cancel := a.newShutdownContext()
+			_ = a.Reload(reloadCtx)
+			cancel()
+		}
+	}
+}
+
+// waitReload waits for the in-flight reload to return,
+// which must be called after the app is marked as stopping.
+func (a *App) waitReload(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		a.reloadMu.Lock()
+		defer a.reloadMu.Unlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+
+	case <-ctx.Done():
+		return fmt.Errorf("app: wait the reload: %w", ctx.Err())
+	}
+}
+
+func (a *App) setModules(modules []Module) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sorted = modules
+}
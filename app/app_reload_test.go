@@ -0,0 +1,244 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type _ReloadModule struct {
+	deps   []string
+	reload func(ctx context.Context, app *App) error
+	Module
+}
+
+func (m _ReloadModule) DependsOn() []string {
+	return m.deps
+}
+
+func (m _ReloadModule) Reload(ctx context.Context, app *App) error {
+	return m.reload(ctx, app)
+}
+
+func TestSetReloadSignals_Panics(t *testing.T) {
+	defer func() { _ = recover() }()
+	New().SetReloadSignals(nil)
+	t.Error("expected panic")
+}
+
+func TestReload_NotReady(t *testing.T) {
+	if err := New().Reload(context.Background()); !errors.Is(err, errNotReady) {
+		t.Errorf("expect error %v, but got %v", errNotReady, err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	app := New()
+	app.SetSignals()
+
+	var loads, config int
+	var loadErr error
+	app.SetConfigLoader(func(ctx context.Context, app *App) error {
+		loads++
+		if loadErr != nil {
+			return loadErr
+		}
+		config = loads
+		return nil
+	})
+
+	var mu sync.Mutex
+	var reloads []string
+	newModule := func(name string, err error, deps ...string) Module {
+		return _ReloadModule{
+			deps:   deps,
+			Module: newTestModule(name),
+			reload: func(ctx context.Context, app *App) error {
+				mu.Lock()
+				reloads = append(reloads, name)
+				mu.Unlock()
+				return err
+			},
+		}
+	}
+
+	app.Use(newModule("http", errors.New("reload fail"), "db"), newTestModule("cache"), newModule("db", nil))
+
+	var events []Event
+	app.Subscribe(ObserverFunc(func(e Event) {
+		if e.Kind == EventReload {
+			events = append(events, e)
+		}
+	}))
+
+	var errs []error
+	app.On(StageReady, func(ctx context.Context, app *App) error {
+		app.Go(func(ctx context.Context) error {
+			errs = append(errs, app.Reload(ctx))
+
+			loadErr = errors.New("load fail")
+			errs = append(errs, app.Reload(ctx))
+
+			app.Stop()
+			return nil
+		})
+		return nil
+	})
+
+	if err := app.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if loads != 3 {
+		t.Errorf("expect to load config %d times, but got %d", 3, loads)
+	}
+	if config != 2 {
+		t.Errorf("expect the config reloaded before the module failure to stay, but got %d", config)
+	}
+	if !slices.Equal(reloads, []string{"db", "http"}) {
+		t.Errorf("expect to reload [db http], but got %v", reloads)
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("expect 2 errors, but got %d", len(errs))
+	}
+	if expect := `app: reload module "http": reload fail`; errs[0] == nil || errs[0].Error() != expect {
+		t.Errorf("expect error %q, but got %v", expect, errs[0])
+	}
+	if expect := `app: reload config: load fail`; errs[1] == nil || errs[1].Error() != expect {
+		t.Errorf("expect error %q, but got %v", expect, errs[1])
+	}
+
+	if len(events) != 2 || events[0].Err == nil || events[1].Err == nil {
+		t.Errorf("unexpected reload events: %+v", events)
+	}
+}
+
+func TestReload_Shutdown(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetSignals()
+
+	var reloadDone, reloadDoneBeforeStopping bool
+	reloading := make(chan struct{})
+	app.Use(_ReloadModule{
+		Module: newTestModule("mod"),
+		reload: func(ctx context.Context, app *App) error {
+			close(reloading)
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond)
+			reloadDone = true
+			return ctx.Err()
+		},
+	})
+	app.On(StageStopping, func(ctx context.Context, app *App) error {
+		reloadDoneBeforeStopping = reloadDone
+		return nil
+	})
+
+	reloadErr := make(chan error, 1)
+	app.On(StageReady, func(ctx context.Context, app *App) error {
+		go func() { reloadErr <- app.Reload(context.Background()) }()
+		go func() { <-reloading; app.Stop() }()
+		return nil
+	})
+
+	if err := app.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-reloadErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expect the reload to be canceled, but got %v", err)
+	}
+	if !reloadDoneBeforeStopping {
+		t.Error("the stopping hooks should run after the reload returns")
+	}
+	if err := app.Reload(context.Background()); !errors.Is(err, errNotReady) {
+		t.Errorf("expect errNotReady, but got %v", err)
+	}
+}
+
+type _TestSignal string
+
+func (s _TestSignal) String() string { return string(s) }
+func (s _TestSignal) Signal()        {}
+
+type _TestNotifier struct {
+	mu sync.Mutex
+	c  chan<- os.Signal
+}
+
+func (n *_TestNotifier) Notify(c chan<- os.Signal, sig ...os.Signal) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.c = c
+}
+
+func (n *_TestNotifier) Stop(c chan<- os.Signal) {}
+
+func (n *_TestNotifier) send(sig os.Signal) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.c <- sig
+}
+
+func TestReload_ShutdownSignal(t *testing.T) {
+	const reload, stop = _TestSignal("reload"), _TestSignal("stop")
+
+	notifier := new(_TestNotifier)
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetShutdownTimeout(3 * time.Second)
+	app.SetSignalNotifier(notifier)
+	app.SetReloadSignals(reload)
+	app.SetSignals(stop)
+
+	reloading := make(chan struct{})
+	reloadErr := make(chan error, 1)
+	app.Use(_ReloadModule{
+		Module: newTestModule("mod"),
+		reload: func(ctx context.Context, app *App) error {
+			close(reloading)
+			<-ctx.Done()
+			reloadErr <- ctx.Err()
+			return ctx.Err()
+		},
+	})
+
+	app.On(StageReady, func(ctx context.Context, app *App) error {
+		go func() {
+			notifier.send(reload)
+			<-reloading
+			notifier.send(stop)
+		}()
+		return nil
+	})
+
+	start := time.Now()
+	if err := app.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if cost := time.Since(start); cost > time.Second {
+		t.Errorf("expect the shutdown signal to cancel the reload, but cost %s", cost)
+	}
+	if err := <-reloadErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expect the reload to be canceled, but got %v", err)
+	}
+}
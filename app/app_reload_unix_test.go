@@ -0,0 +1,63 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package app
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReload_Signal(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetReloadSignals(syscall.SIGHUP)
+	app.SetSignals()
+
+	reloaded := make(chan struct{})
+	app.Use(_ReloadModule{
+		Module: newTestModule("mod"),
+		reload: func(ctx context.Context, app *App) error {
+			close(reloaded)
+			return nil
+		},
+	})
+
+	app.On(StageReady, func(ctx context.Context, app *App) error {
+		p, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			return err
+		}
+		return p.Signal(syscall.SIGHUP)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-reloaded:
+		case <-time.After(time.Second):
+			t.Error("the app is not reloaded")
+		}
+		cancel()
+	}()
+
+	if err := app.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
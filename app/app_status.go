@@ -0,0 +1,176 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ModuleState is the lifecycle state of a module.
+type ModuleState string
+
+const (
+	ModulePending      ModuleState = "pending"
+	ModuleInitializing ModuleState = "initializing"
+	ModuleInitialized  ModuleState = "initialized"
+	ModuleStarting     ModuleState = "starting"
+	ModuleStarted      ModuleState = "started"
+	ModuleStopping     ModuleState = "stopping"
+	ModuleStopped      ModuleState = "stopped"
+	ModuleFailed       ModuleState = "failed"
+)
+
+// ModuleStatus is the runtime status of a registered module.
+type ModuleStatus struct {
+	Name      string      `json:"name"`
+	State     ModuleState `json:"state"`
+	DependsOn []string    `json:"depends_on,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	UpdatedAt time.Time   `json:"updated_at,omitzero"`
+}
+
+// TaskStatus is the runtime status of a running background task.
+type TaskStatus struct {
+	Name      string    `json:"name"`
+	StartedAt time.Time `json:"started_at"`
+	Restarts  int       `json:"restarts"`
+}
+
+type statusRegistry struct {
+	mu      sync.Mutex
+	modules map[string]ModuleStatus
+	tasks   map[*taskStatus]struct{}
+}
+
+type taskStatus struct {
+	name     string
+	started  time.Time
+	restarts int
+}
+
+// Modules returns the status of all the registered modules,
+// which are in the order of the initialization once the app runs.
+func (a *App) Modules() []ModuleStatus {
+	a.mu.Lock()
+	modules := a.sorted
+	if modules == nil {
+		modules = a.modules
+	}
+	modules = slices.Clone(modules)
+	a.mu.Unlock()
+
+	a.status.mu.Lock()
+	defer a.status.mu.Unlock()
+
+	statuses := make([]ModuleStatus, len(modules))
+	for i, m := range modules {
+		status, ok := a.status.modules[m.Name()]
+		if !ok {
+			status = ModuleStatus{Name: m.Name(), State: ModulePending}
+		}
+
+		if d, ok := m.(Dependent); ok {
+			status.DependsOn = slices.Clone(d.DependsOn())
+		}
+
+		statuses[i] = status
+	}
+
+	return statuses
+}
+
+// Tasks returns the status of the running background tasks,
+// which are sorted by the name and the start time.
+func (a *App) Tasks() []TaskStatus {
+	a.status.mu.Lock()
+	tasks := make([]TaskStatus, 0, len(a.status.tasks))
+	for t := range a.status.tasks {
+		tasks = append(tasks, TaskStatus{Name: t.name, StartedAt: t.started, Restarts: t.restarts})
+	}
+	a.status.mu.Unlock()
+
+	slices.SortFunc(tasks, func(a, b TaskStatus) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+
+	return tasks
+}
+
+func (a *App) setModuleState(name, action string, done bool, err error) {
+	var state ModuleState
+	switch {
+	case err != nil:
+		state = ModuleFailed
+	case action == "init" && done:
+		state = ModuleInitialized
+	case action == "init":
+		state = ModuleInitializing
+	case action == "start" && done:
+		state = ModuleStarted
+	case action == "start":
+		state = ModuleStarting
+	case action == "stop" && done:
+		state = ModuleStopped
+	case action == "stop":
+		state = ModuleStopping
+	default:
+		return
+	}
+
+	status := ModuleStatus{Name: name, State: state, UpdatedAt: time.Now()}
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	a.status.mu.Lock()
+	defer a.status.mu.Unlock()
+
+	if a.status.modules == nil {
+		a.status.modules = make(map[string]ModuleStatus)
+	}
+	a.status.modules[name] = status
+}
+
+func (a *App) addTask(name string) *taskStatus {
+	t := &taskStatus{name: name, started: time.Now()}
+
+	a.status.mu.Lock()
+	defer a.status.mu.Unlock()
+
+	if a.status.tasks == nil {
+		a.status.tasks = make(map[*taskStatus]struct{})
+	}
+	a.status.tasks[t] = struct{}{}
+	return t
+}
+
+func (a *App) restartTask(t *taskStatus, restarts int) {
+	a.status.mu.Lock()
+	defer a.status.mu.Unlock()
+	t.restarts = restarts
+	t.started = time.Now()
+}
+
+func (a *App) removeTask(t *taskStatus) {
+	a.status.mu.Lock()
+	defer a.status.mu.Unlock()
+	delete(a.status.tasks, t)
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func moduleStates(statuses []ModuleStatus) []ModuleState {
+	states := make([]ModuleState, len(statuses))
+	for i, s := range statuses {
+		states[i] = s.State
+	}
+	return states
+}
+
+func TestModulesAndTasks(t *testing.T) {
+	app := New()
+	app.SetSignals()
+	app.SetConfigLoader(func(context.Context, *App) error { return nil })
+	app.Use(newTestModule("db"), newDependentModule(newTestModule("http"), "db"))
+
+	if states := moduleStates(app.Modules()); !slices.Equal(states, []ModuleState{ModulePending, ModulePending}) {
+		t.Errorf("expect the pending modules, but got %v", states)
+	}
+
+	var modules []ModuleStatus
+	var tasks []TaskStatus
+	app.On(StageReady, func(ctx context.Context, app *App) error {
+		app.GoNamed("worker", func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		})
+
+		modules = app.Modules()
+		tasks = app.Tasks()
+		app.Stop()
+		return nil
+	})
+
+	if err := app.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if states := moduleStates(modules); !slices.Equal(states, []ModuleState{ModuleStarted, ModuleStarted}) {
+		t.Errorf("expect the started modules, but got %v", states)
+	}
+	if len(modules) == 2 && !slices.Equal(modules[1].DependsOn, []string{"db"}) {
+		t.Errorf("expect the dependencies %v, but got %v", []string{"db"}, modules[1].DependsOn)
+	}
+
+	if len(tasks) != 1 || tasks[0].Name != "worker" {
+		t.Errorf("expect the task 'worker', but got %v", tasks)
+	}
+
+	if states := moduleStates(app.Modules()); !slices.Equal(states, []ModuleState{ModuleStopped, ModuleStopped}) {
+		t.Errorf("expect the stopped modules, but got %v", states)
+	}
+	if tasks := app.Tasks(); len(tasks) != 0 {
+		t.Errorf("expect no running tasks, but got %v", tasks)
+	}
+}
+
+func TestModulesFailed(t *testing.T) {
+	app := New()
+	app.SetSignals()
+	app.SetConfigLoader(func(context.Context, *App) error { return nil })
+	app.Use(newTestModule("init-err"))
+
+	if err := app.Run(context.Background()); err == nil {
+		t.Fatal("expect an error, but got nil")
+	}
+
+	modules := app.Modules()
+	if len(modules) != 1 || modules[0].State != ModuleFailed || modules[0].Error == "" {
+		t.Errorf("expect the failed module with the error, but got %v", modules)
+	}
+}
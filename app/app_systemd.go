@@ -0,0 +1,102 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// The states sent to the systemd service manager.
+const (
+	SdNotifyReady    = "READY=1"
+	SdNotifyStopping = "STOPPING=1"
+	SdNotifyWatchdog = "WATCHDOG=1"
+)
+
+// SdNotify sends the state to the systemd service manager
+// by the unix datagram socket $NOTIFY_SOCKET, such as "READY=1".
+//
+// If NOTIFY_SOCKET is unset, it does nothing and returns nil.
+func SdNotify(state string) (err error) {
+	addr := os.Getenv("NOTIFY_SOCKET")
+	if addr == "" {
+		return
+	}
+
+	// The address starting with "@" is an abstract socket on Linux,
+	// which is handled by the net package.
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("app: sd_notify: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("app: sd_notify: %w", err)
+	}
+	return
+}
+
+// SdWatchdogInterval returns the interval of the systemd watchdog
+// from $WATCHDOG_USEC, within which the service must send "WATCHDOG=1".
+//
+// Return 0 if the watchdog is not enabled for the current process.
+func SdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond
+}
+
+func (a *App) sdNotify(state string) {
+	if err := SdNotify(state); err != nil {
+		slog.Error("fail to notify systemd", "state", state, "err", err)
+	}
+}
+
+// sdWatchdog sends "WATCHDOG=1" to systemd at half of the watchdog interval
+// until ctx is done.
+func (a *App) sdWatchdog(ctx context.Context) {
+	interval := SdWatchdogInterval()
+	if interval <= 0 || os.Getenv("NOTIFY_SOCKET") == "" {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.sdNotify(SdNotifyWatchdog)
+			}
+		}
+	}()
+}
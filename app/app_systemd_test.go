@@ -0,0 +1,112 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func listenNotifySocket(t *testing.T) *net.UnixConn {
+	if runtime.GOOS == "windows" {
+		t.Skip("unixgram is not supported on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", path)
+	return conn
+}
+
+func readNotify(t *testing.T, conn *net.UnixConn) string {
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("fail to read the notification: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestSdNotify_Unset(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if err := SdNotify(SdNotifyReady); err != nil {
+		t.Errorf("expect nil, but got %v", err)
+	}
+}
+
+func TestSdWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "")
+	if interval := SdWatchdogInterval(); interval != 0 {
+		t.Errorf("expect interval 0, but got %s", interval)
+	}
+
+	t.Setenv("WATCHDOG_USEC", "2000000")
+	t.Setenv("WATCHDOG_PID", "")
+	if interval := SdWatchdogInterval(); interval != time.Second*2 {
+		t.Errorf("expect interval %s, but got %s", time.Second*2, interval)
+	}
+
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	if interval := SdWatchdogInterval(); interval != 0 {
+		t.Errorf("expect interval 0 for another process, but got %s", interval)
+	}
+}
+
+func TestSdNotify_App(t *testing.T) {
+	conn := listenNotifySocket(t)
+	t.Setenv("WATCHDOG_USEC", "20000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+
+	app := New()
+	app.SetSignals()
+	app.SetConfigLoader(func(context.Context, *App) error { return nil })
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- app.Run(context.Background()) }()
+
+	if state := readNotify(t, conn); state != SdNotifyReady {
+		t.Errorf("expect state '%s', but got '%s'", SdNotifyReady, state)
+	}
+	if state := readNotify(t, conn); state != SdNotifyWatchdog {
+		t.Errorf("expect state '%s', but got '%s'", SdNotifyWatchdog, state)
+	}
+
+	app.Stop()
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+
+	// Skip the remaining watchdog notifications.
+	for {
+		state := readNotify(t, conn)
+		if state == SdNotifyStopping {
+			break
+		} else if state != SdNotifyWatchdog {
+			t.Fatalf("expect state '%s', but got '%s'", SdNotifyStopping, state)
+		}
+	}
+}
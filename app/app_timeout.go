@@ -0,0 +1,176 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ModuleTimeout is an optional interface that a Module may implement
+// to override the timeout of its Init and Start.
+//
+// stage is either StageInit for Init or StageStart for Start.
+// If it returns a non-positive duration, no timeout is set for the module,
+// but the module is still limited by the timeout of the stage.
+type ModuleTimeout interface {
+	LifecycleTimeout(stage Stage) time.Duration
+}
+
+// SetStageTimeout sets the timeout of the lifecycle phase
+// that starts at stage, which must be one of
+//
+//   - StageInit: the StageInit hooks and Module.Init
+//   - StageStart: the StageStart hooks and Module.Start
+//   - StageReady: the StageReady hooks
+//
+// When the timeout fires, Run stops waiting for the stuck hook or module
+// and fails with an error naming it. The stuck call is not interrupted,
+// but the context passed to it is canceled. The shutdown waits for it,
+// limited by the shutdown timeout, before stopping the modules,
+// and a module whose Init succeeds late is stopped as well.
+//
+// If timeout is 0, the phase has no timeout, which is the default.
+//
+// It must be called before Run.
+func (a *App) SetStageTimeout(stage Stage, timeout time.Duration) {
+	switch stage {
+	case StageInit, StageStart, StageReady:
+	default:
+		panic(fmt.Sprintf("app: cannot set timeout for stage %q", stage))
+	}
+
+	if timeout < 0 {
+		panic("app: stage timeout must not be negative")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.mustBeNewLocked("SetStageTimeout")
+	a.stageTimeouts[stage] = timeout
+}
+
+func (a *App) newStageContext(ctx context.Context, stage Stage) (context.Context, context.CancelFunc) {
+	a.mu.Lock()
+	timeout := a.stageTimeouts[stage]
+	a.mu.Unlock()
+
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, timeout, timeoutError{timeout: timeout})
+}
+
+func (a *App) callModule(ctx context.Context, m Module, stage Stage, call func(context.Context, *App) error, late func()) error {
+	var timeout time.Duration
+	if t, ok := m.(ModuleTimeout); ok {
+		timeout = t.LifecycleTimeout(stage)
+	}
+
+	return a.observeModule(m, string(stage), func() error {
+		return a.callContext(ctx, timeout, func(ctx context.Context) error { return call(ctx, a) }, late)
+	})
+}
+
+// callContext calls fn with ctx, but returns the cause of ctx
+// without waiting for fn once ctx is done.
+//
+// If timeout is positive, ctx is limited by it. If ctx has no deadline,
+// fn is called directly.
+//
+// The abandoned fn keeps running in the background, which is waited for
+// by waitAbandoned. If it returns nil at last, late is called if not nil.
+func (a *App) callContext(ctx context.Context, timeout time.Duration, fn func(context.Context) error, late func()) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, timeoutError{timeout: timeout})
+		defer cancel()
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		return fn(ctx)
+	}
+
+	type result struct {
+		err   error
+		panic any
+	}
+
+	// settled is set by whichever of fn and the caller finishes first.
+	var settled atomic.Bool
+	done := make(chan result, 1)
+
+	a.abandoned.Add(1)
+	go func() {
+		var r result
+		defer func() {
+			defer a.abandoned.Done()
+
+			r.panic = recover()
+			if !settled.Swap(true) {
+				done <- r
+			} else if late != nil && r.panic == nil && r.err == nil {
+				late()
+			}
+		}()
+		r.err = fn(ctx)
+	}()
+
+	select {
+	case r := <-done:
+		if r.panic != nil {
+			panic(r.panic)
+		}
+		return r.err
+
+	case <-ctx.Done():
+		if settled.Swap(true) {
+			// fn has just finished, which is handled as the late one.
+			if r := <-done; late != nil && r.panic == nil && r.err == nil {
+				late()
+			}
+		}
+		return context.Cause(ctx)
+	}
+}
+
+// waitAbandoned waits for the calls abandoned by callContext.
+func (a *App) waitAbandoned(ctx context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		a.abandoned.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+
+	case <-ctx.Done():
+		return fmt.Errorf("app: wait abandoned calls: %w", ctx.Err())
+	}
+}
+
+type timeoutError struct {
+	timeout time.Duration
+}
+
+func (e timeoutError) Error() string { return fmt.Sprintf("timed out after %s", e.timeout) }
+func (e timeoutError) Unwrap() error { return context.DeadlineExceeded }
+func (e timeoutError) Timeout() bool { return true }
@@ -0,0 +1,192 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type _TimeoutModule struct {
+	timeout time.Duration
+	Module
+}
+
+func (m _TimeoutModule) LifecycleTimeout(stage Stage) time.Duration {
+	if stage == StageInit {
+		return m.timeout
+	}
+	return 0
+}
+
+func TestSetStageTimeout_Panics(t *testing.T) {
+	func() {
+		defer func() { _ = recover() }()
+		New().SetStageTimeout(StageStopping, time.Second)
+		t.Error("expected panic for invalid stage")
+	}()
+
+	func() {
+		defer func() { _ = recover() }()
+		New().SetStageTimeout(StageInit, -time.Second)
+		t.Error("expected panic for negative timeout")
+	}()
+}
+
+func TestStageTimeout_Hook(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetStageTimeout(StageInit, 50*time.Millisecond)
+	app.SetSignals()
+
+	app.OnNamed(StageInit, "stuck", func(ctx context.Context, app *App) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	err := app.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expect := `app: hook "stuck" at stage "init": timed out after 50ms`
+	if s := err.Error(); !strings.HasPrefix(s, expect) {
+		t.Errorf("expect error %q, but got %q", expect, s)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("expect a context.DeadlineExceeded error")
+	}
+}
+
+func TestStageTimeout_Module(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetStageTimeout(StageStart, 50*time.Millisecond)
+	app.SetSignals()
+
+	var started atomic.Bool
+	mod := newTestModule("stuck")
+	mod.start = func(ctx context.Context, app *App) error {
+		<-ctx.Done()
+		time.Sleep(100 * time.Millisecond)
+		started.Store(true)
+		return nil
+	}
+	mod.stop = func(ctx context.Context, app *App) error {
+		if !started.Load() {
+			t.Error("the module is stopped before the abandoned start returns")
+		}
+		return nil
+	}
+	app.Use(mod)
+
+	err := app.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expect := `app: start module "stuck": timed out after 50ms`
+	if s := err.Error(); !strings.HasPrefix(s, expect) {
+		t.Errorf("expect error %q, but got %q", expect, s)
+	}
+}
+
+func TestStageTimeout_LateInit(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetStageTimeout(StageInit, 50*time.Millisecond)
+	app.SetSignals()
+
+	mod := newTestModule("late")
+	mod.init = func(ctx context.Context, app *App) error {
+		time.Sleep(150 * time.Millisecond)
+		atomic.AddInt32(mod.initCalled, 1)
+		return nil
+	}
+	app.Use(mod)
+
+	err := app.Run(context.Background())
+	expect := `app: init module "late": timed out after 50ms`
+	if err == nil || !strings.HasPrefix(err.Error(), expect) {
+		t.Errorf("expect error %q, but got %v", expect, err)
+	}
+
+	if n := atomic.LoadInt32(mod.initCalled); n != 1 {
+		t.Errorf("expect init to finish before Run returns, but got %d", n)
+	}
+	if n := atomic.LoadInt32(mod.stopCalled); n != 1 {
+		t.Errorf("expect the late initialized module to be stopped, but got %d", n)
+	}
+}
+
+func TestStageTimeout_ModuleOverride(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetStageTimeout(StageInit, time.Second)
+	app.SetSignals()
+
+	mod := newTestModule("slow")
+	mod.init = func(ctx context.Context, app *App) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	app.Use(_TimeoutModule{timeout: 20 * time.Millisecond, Module: mod})
+
+	err := app.Run(context.Background())
+	expect := `app: init module "slow": timed out after 20ms`
+	if err == nil || !strings.HasPrefix(err.Error(), expect) {
+		t.Errorf("expect error %q, but got %v", expect, err)
+	}
+}
+
+func TestStageTimeout_NotExceeded(t *testing.T) {
+	app := New()
+	app.SetConfigLoader(func(ctx context.Context, app *App) error { return nil })
+	app.SetStageTimeout(StageInit, time.Second)
+	app.SetStageTimeout(StageReady, time.Second)
+	app.SetSignals()
+
+	app.Use(newTestModule("mod"))
+	app.On(StageReady, func(ctx context.Context, app *App) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("expect the ready hook context to have a deadline")
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { time.Sleep(50 * time.Millisecond); cancel() }()
+	if err := app.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCallContext_Panic(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("expect panic %q, but got %v", "boom", r)
+		}
+	}()
+
+	_ = New().callContext(ctx, 0, func(context.Context) error { panic("boom") }, nil)
+	t.Error("expected panic")
+}
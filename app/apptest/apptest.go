@@ -0,0 +1,267 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apptest provides a test harness for the app lifecycle.
+//
+// Example:
+//
+//	func TestServer(t *testing.T) {
+//		h := apptest.New(t, newDBModule(), newServerModule())
+//		h.Start()
+//
+//		// ... test the running app
+//
+//		h.Signal(os.Interrupt)
+//		if err := h.Wait(); err != nil {
+//			t.Fatal(err)
+//		}
+//
+//		expects := []string{
+//			"module:db:init", "module:server:init",
+//			"module:db:start", "module:server:start",
+//			"module:server:stop", "module:db:stop",
+//		}
+//		if calls := h.ModuleCalls(); !slices.Equal(calls, expects) {
+//			t.Errorf("expect calls %v, but got %v", expects, calls)
+//		}
+//	}
+package apptest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-toolkit/app"
+)
+
+// DefaultTimeout is the default timeout to wait for the app.
+var DefaultTimeout = time.Second * 10
+
+// Harness is a test harness running an isolated app in the background.
+type Harness struct {
+	// App is the isolated app, which may be configured before Start,
+	// such as registering the modules and the hooks.
+	App *app.App
+
+	t       testing.TB
+	signals *Signals
+	timeout time.Duration
+
+	lock   sync.Mutex
+	events []app.Event
+
+	once  sync.Once
+	done  chan struct{}
+	err   error
+	ready chan struct{}
+}
+
+// New returns a new test harness with an isolated app using the modules.
+//
+// The app does not parse the command line flags, and the signals set by
+// App.SetSignals and App.SetReloadSignals are delivered only by Signal.
+func New(t testing.TB, modules ...app.Module) *Harness {
+	h := &Harness{
+		App:     app.New(),
+		t:       t,
+		signals: NewSignals(),
+		timeout: DefaultTimeout,
+		done:    make(chan struct{}),
+		ready:   make(chan struct{}),
+	}
+
+	h.App.SetName(t.Name())
+	h.App.SetSignalNotifier(h.signals)
+	h.App.SetConfigLoader(func(context.Context, *app.App) error { return nil })
+	h.App.Subscribe(app.ObserverFunc(h.observe))
+	h.App.Use(modules...)
+	return h
+}
+
+// SetTimeout resets the timeout to wait for the app.
+//
+// Default: DefaultTimeout
+func (h *Harness) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		panic("apptest: timeout must be greater than 0")
+	}
+	h.timeout = timeout
+}
+
+func (h *Harness) observe(e app.Event) {
+	h.lock.Lock()
+	h.events = append(h.events, e)
+	h.lock.Unlock()
+}
+
+// Start runs the app in the background, and waits until it is ready.
+//
+// If the app fails before being ready, or it is not ready within the timeout,
+// the test fails immediately. The app is stopped when the test finishes.
+func (h *Harness) Start() {
+	h.t.Helper()
+
+	h.once.Do(func() {
+		// Register it at last so that it runs after all the ready hooks.
+		h.App.OnNamed(app.StageReady, "apptest", func(context.Context, *app.App) error {
+			close(h.ready)
+			return nil
+		})
+
+		go func() {
+			h.err = h.App.Run(context.Background())
+			close(h.done)
+		}()
+
+		h.t.Cleanup(func() {
+			h.App.Stop()
+			select {
+			case <-h.done:
+			case <-time.After(h.timeout):
+				h.t.Errorf("apptest: the app does not exit within %s", h.timeout)
+			}
+		})
+	})
+
+	select {
+	case <-h.ready:
+	case <-h.done:
+		h.t.Fatalf("apptest: the app exits before being ready: %v", h.err)
+	case <-time.After(h.timeout):
+		h.t.Fatalf("apptest: the app is not ready within %s", h.timeout)
+	}
+}
+
+// Stop requests the app to stop, and waits until it exits.
+//
+// It returns the final error returned by App.Run.
+func (h *Harness) Stop() error {
+	h.t.Helper()
+	h.App.Stop()
+	return h.Wait()
+}
+
+// Signal delivers the fake signal to the app, such as os.Interrupt
+// to shut down, or syscall.SIGHUP to reload.
+func (h *Harness) Signal(sig os.Signal) {
+	h.signals.Send(sig)
+}
+
+// Fail injects a failure of a background task with err,
+// which makes the app shut down and return the error.
+func (h *Harness) Fail(err error) {
+	if err == nil {
+		panic("apptest: the injected error must not be nil")
+	}
+	h.App.GoNamed("apptest", func(context.Context) error { return err })
+}
+
+// Wait waits until the app exits, and returns the final error
+// returned by App.Run. The test fails if it times out.
+func (h *Harness) Wait() error {
+	h.t.Helper()
+
+	select {
+	case <-h.done:
+		return h.err
+	case <-time.After(h.timeout):
+		h.t.Fatalf("apptest: the app does not exit within %s", h.timeout)
+		return nil
+	}
+}
+
+// Events returns all the lifecycle events emitted by the app in order.
+func (h *Harness) Events() []app.Event {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return slices.Clone(h.events)
+}
+
+// Calls returns the ordered calls of the modules and the hooks,
+// the format of which is "module:NAME:ACTION" and "hook:STAGE:NAME".
+//
+// The name of an unnamed hook is "#index", and the hook registered
+// by the harness itself is excluded.
+func (h *Harness) Calls() []string {
+	return h.calls(true)
+}
+
+// ModuleCalls is the same as Calls, but only returns the calls of the modules.
+func (h *Harness) ModuleCalls() []string {
+	return h.calls(false)
+}
+
+func (h *Harness) calls(withHooks bool) []string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	calls := make([]string, 0, len(h.events))
+	for _, e := range h.events {
+		switch {
+		case e.Kind == app.EventModuleBegin:
+			calls = append(calls, fmt.Sprintf("module:%s:%s", e.Name, e.Action))
+
+		case e.Kind == app.EventHookBegin && withHooks && e.Name != "apptest":
+			calls = append(calls, fmt.Sprintf("hook:%s:%s", e.Stage, e.Name))
+		}
+	}
+	return calls
+}
+
+// NewSignals returns a new fake signal notifier, which implements
+// the interface app.SignalNotifier.
+func NewSignals() *Signals {
+	return &Signals{chans: make(map[chan<- os.Signal][]os.Signal)}
+}
+
+// Signals is a fake signal notifier to deliver the signals by Send.
+type Signals struct {
+	lock  sync.Mutex
+	chans map[chan<- os.Signal][]os.Signal
+}
+
+// Notify implements the interface app.SignalNotifier.
+func (s *Signals) Notify(c chan<- os.Signal, sig ...os.Signal) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.chans[c] = append(s.chans[c], sig...)
+}
+
+// Stop implements the interface app.SignalNotifier.
+func (s *Signals) Stop(c chan<- os.Signal) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.chans, c)
+}
+
+// Send delivers the signal to the channels that are notified for it,
+// which is dropped if the channel is full like the package os/signal.
+func (s *Signals) Send(sig os.Signal) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for c, sigs := range s.chans {
+		if slices.Contains(sigs, sig) {
+			select {
+			case c <- sig:
+			default:
+			}
+		}
+	}
+}
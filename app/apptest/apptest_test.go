@@ -0,0 +1,107 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apptest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/xgfone/go-toolkit/app"
+)
+
+type testModule struct {
+	name    string
+	stopErr error
+	reloads chan struct{}
+}
+
+func (m testModule) Name() string                          { return m.name }
+func (m testModule) Init(context.Context, *app.App) error  { return nil }
+func (m testModule) Start(context.Context, *app.App) error { return nil }
+func (m testModule) Stop(context.Context, *app.App) error  { return m.stopErr }
+
+func (m testModule) Reload(context.Context, *app.App) error {
+	if m.reloads != nil {
+		m.reloads <- struct{}{}
+	}
+	return nil
+}
+
+// testSignal is a fake signal, since not all platforms have SIGHUP.
+type testSignal string
+
+func (s testSignal) String() string { return string(s) }
+func (s testSignal) Signal()        {}
+
+func TestHarnessSignal(t *testing.T) {
+	reload := testSignal("reload")
+	reloads := make(chan struct{}, 1)
+	h := New(t, testModule{name: "db", reloads: reloads}, testModule{name: "server"})
+	h.App.SetReloadSignals(reload)
+	h.App.OnNamed(app.StageStopping, "drain", func(context.Context, *app.App) error { return nil })
+	h.Start()
+
+	if !h.App.Ready() {
+		t.Error("expect the app is ready, but not")
+	}
+
+	h.Signal(reload)
+	<-reloads
+
+	h.Signal(os.Interrupt)
+	if err := h.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	expects := []string{
+		"module:db:init", "module:server:init",
+		"module:db:start", "module:server:start",
+		"hook:stopping:drain",
+		"module:server:stop", "module:db:stop",
+	}
+	if calls := h.Calls(); !slices.Equal(calls, expects) {
+		t.Errorf("expect calls %v, but got %v", expects, calls)
+	}
+}
+
+func TestHarnessFail(t *testing.T) {
+	errStop := errors.New("stop fail")
+	errTask := errors.New("task fail")
+
+	h := New(t, testModule{name: "db", stopErr: errStop})
+	h.Start()
+	h.Fail(errTask)
+
+	err := h.Wait()
+	if !errors.Is(err, errTask) || !errors.Is(err, errStop) {
+		t.Errorf("expect the joined errors of %v and %v, but got %v", errTask, errStop, err)
+	}
+
+	expects := []string{"module:db:init", "module:db:start", "module:db:stop"}
+	if calls := h.ModuleCalls(); !slices.Equal(calls, expects) {
+		t.Errorf("expect calls %v, but got %v", expects, calls)
+	}
+}
+
+func TestHarnessStop(t *testing.T) {
+	h := New(t)
+	h.Start()
+	if err := h.Stop(); err != nil {
+		t.Errorf("expect nil, but got %v", err)
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package command provides the subcommand support for the app-based binaries,
+// such as "serve", "migrate", "version" and "config check".
+//
+// Example:
+//
+//	cmds := command.New(app.DefaultApp)
+//	cmds.SetDefault("serve")
+//	cmds.Add(&command.Command{
+//		Name:    "serve",
+//		Short:   "Start the http server.",
+//		Modules: []app.Module{server},
+//	})
+//	cmds.Add(&command.Command{
+//		Name:  "migrate",
+//		Short: "Migrate the database schema.",
+//		Run: func(ctx context.Context, a *app.App, args []string) error {
+//			return migrate(ctx)
+//		},
+//	})
+//	cmds.Main()
+package command
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/xgfone/go-toolkit/app"
+)
+
+// Command is a subcommand of the app-based binary.
+type Command struct {
+	// Name is the name of the command, which may consist of multiple
+	// words separated by the space, such as "config check".
+	Name string
+
+	// Short is the one-line description shown in the command list.
+	Short string
+
+	// Long is the detailed description shown in the help of the command.
+	Long string
+
+	// Usage is the usage of the arguments after the flags, such as "[FILE...]".
+	Usage string
+
+	// Flags is used to register the flags of the command, which is optional.
+	Flags func(fs *flag.FlagSet)
+
+	// Modules is the modules used by the app when running the command.
+	Modules []app.Module
+
+	// Setup is called after parsing the flags and before running the app,
+	// which is optional and may be used to register the hooks, the config
+	// loader and the modules depending on the flags, etc.
+	Setup func(a *app.App, fs *flag.FlagSet) error
+
+	// Run is the one-shot action of the command, which is optional.
+	//
+	// If set, it is run after the app is ready, and the app is stopped
+	// when it returns. Or, the app runs until it is stopped, such as "serve".
+	Run func(ctx context.Context, a *app.App, args []string) error
+
+	// action runs the command without the app lifecycle, such as "help".
+	action func(fs *flag.FlagSet) error
+}
+
+// Commands is the set of the subcommands of an app-based binary.
+type Commands struct {
+	app      *app.App
+	commands []*Command
+	defname  string
+	output   io.Writer
+}
+
+// New returns a new commands set based on the app,
+// which has registered the built-in commands "help" and "version".
+func New(a *app.App) *Commands {
+	if a == nil {
+		panic("command: nil app")
+	}
+
+	c := &Commands{app: a, output: os.Stdout}
+	c.Add(&Command{
+		Name:   "help",
+		Short:  "Show the help of the command.",
+		Usage:  "[COMMAND]",
+		action: c.help,
+	})
+	c.Add(&Command{
+		Name:   "version",
+		Short:  "Print the version information.",
+		action: c.version,
+	})
+	return c
+}
+
+// SetOutput resets the output of the help and version information.
+//
+// Default: os.Stdout
+func (c *Commands) SetOutput(w io.Writer) {
+	if w == nil {
+		panic("command: nil output")
+	}
+	c.output = w
+}
+
+// SetDefault sets the name of the command to run when no command is given.
+func (c *Commands) SetDefault(name string) {
+	c.defname = name
+}
+
+// Add adds the commands, which panics if a command has been added.
+func (c *Commands) Add(cmds ...*Command) {
+	for _, cmd := range cmds {
+		if cmd == nil {
+			panic("command: nil command")
+		}
+
+		cmd.Name = strings.Join(strings.Fields(cmd.Name), " ")
+		if cmd.Name == "" {
+			panic("command: empty command name")
+		}
+
+		if c.Get(cmd.Name) != nil {
+			panic(fmt.Sprintf("command: command %q has been added", cmd.Name))
+		}
+
+		c.commands = append(c.commands, cmd)
+	}
+
+	slices.SortFunc(c.commands, func(a, b *Command) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// Get returns the command by the name, or nil if not found.
+func (c *Commands) Get(name string) *Command {
+	name = strings.Join(strings.Fields(name), " ")
+	for _, cmd := range c.commands {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// Main is a convenience function to run the command by os.Args[1:],
+// which prints the error and exits with the code 1 if failing.
+func (c *Commands) Main() {
+	if err := c.Run(context.Background(), os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		osexit(1)
+	}
+}
+
+var osexit = os.Exit
+
+// Run finds the command by the leading arguments and runs it
+// with the rest arguments.
+//
+// For the command with Run, it runs the app through Init and Start,
+// then calls Run after the app is ready and shuts the app down.
+// For the command without Run, it runs the app until it is stopped.
+//
+// Since the arguments after the command name are parsed by the flag set
+// of the command, flag.CommandLine is marked as parsed with no arguments
+// before running the app, so that the config loader parsing it, such as
+// the default one of the app, does not parse the command flags again.
+func (c *Commands) Run(ctx context.Context, args []string) (err error) {
+	cmd, args := c.find(args)
+	if cmd == nil {
+		c.usage(c.output)
+		if len(args) == 0 {
+			return errors.New("command: missing command")
+		}
+		return fmt.Errorf("command: unknown command %q", args[0])
+	}
+
+	fs := c.newFlagSet(cmd)
+	if err = fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			err = nil
+		}
+		return
+	}
+
+	if cmd.action != nil {
+		return cmd.action(fs)
+	}
+
+	if cmd.Setup != nil {
+		if err = cmd.Setup(c.app, fs); err != nil {
+			return
+		}
+	}
+
+	c.app.Use(cmd.Modules...)
+	if cmd.Run != nil {
+		run, args := cmd.Run, fs.Args()
+		c.app.OnNamed(app.StageReady, cmd.Name, func(_ context.Context, a *app.App) error {
+			a.GoNamed(cmd.Name, func(ctx context.Context) error {
+				if err := run(ctx, a, args); err != nil {
+					return err
+				}
+
+				a.Stop()
+				return nil
+			})
+			return nil
+		})
+	}
+
+	// All the arguments after the command name belong to the command,
+	// so none is left for the global flag set parsed by the config loader,
+	// such as the default one, which would parse os.Args[1:] otherwise.
+	if !flag.CommandLine.Parsed() {
+		_ = flag.CommandLine.Parse(nil)
+	}
+
+	return c.app.Run(ctx)
+}
+
+// find returns the command matching the longest leading arguments
+// and the rest arguments.
+func (c *Commands) find(args []string) (*Command, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if c.defname == "" {
+			return nil, args
+		}
+		return c.Get(c.defname), args
+	}
+
+	for i := len(args); i > 0; i-- {
+		if cmd := c.Get(strings.Join(args[:i], " ")); cmd != nil {
+			return cmd, args[i:]
+		}
+	}
+
+	return nil, args
+}
+
+func (c *Commands) newFlagSet(cmd *Command) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.app.Name()+" "+cmd.Name, flag.ContinueOnError)
+	fs.SetOutput(c.output)
+	fs.Usage = func() { c.commandUsage(fs.Output(), cmd, fs) }
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+	return fs
+}
+
+func (c *Commands) usage(w io.Writer) {
+	name := c.app.Name()
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [arguments]\n\nCommands:\n", name)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, cmd := range c.commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.Name, cmd.Short)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\nRun '%s help <command>' for more information about a command.\n", name)
+}
+
+func (c *Commands) commandUsage(w io.Writer, cmd *Command, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: %s %s [flags]", c.app.Name(), cmd.Name)
+	if cmd.Usage != "" {
+		fmt.Fprintf(w, " %s", cmd.Usage)
+	}
+	fmt.Fprintln(w)
+
+	switch {
+	case cmd.Long != "":
+		fmt.Fprintf(w, "\n%s\n", strings.TrimSpace(cmd.Long))
+	case cmd.Short != "":
+		fmt.Fprintf(w, "\n%s\n", cmd.Short)
+	}
+
+	var hasFlags bool
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintf(w, "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+}
+
+func (c *Commands) help(fs *flag.FlagSet) error {
+	if fs.NArg() == 0 {
+		c.usage(c.output)
+		return nil
+	}
+
+	name := strings.Join(fs.Args(), " ")
+	cmd := c.Get(name)
+	if cmd == nil {
+		return fmt.Errorf("command: unknown command %q", name)
+	}
+
+	c.commandUsage(c.output, cmd, c.newFlagSet(cmd))
+	return nil
+}
+
+func (c *Commands) version(*flag.FlagSet) error {
+	buildTime := "unknown"
+	if t := c.app.BuildTime(); t.Unix() != 0 {
+		buildTime = t.Format(time.RFC3339)
+	}
+
+	commit := c.app.Commit()
+	if commit == "" {
+		commit = "unknown"
+	}
+
+	tw := tabwriter.NewWriter(c.output, 0, 4, 1, ' ', 0)
+	fmt.Fprintf(tw, "Name:\t%s\n", c.app.Name())
+	fmt.Fprintf(tw, "Version:\t%s\n", c.app.Version())
+	fmt.Fprintf(tw, "Commit:\t%s\n", commit)
+	fmt.Fprintf(tw, "BuildTime:\t%s\n", buildTime)
+	return tw.Flush()
+}
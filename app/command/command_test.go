@@ -0,0 +1,242 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-toolkit/app"
+)
+
+type testModule struct {
+	name  string
+	calls *[]string
+}
+
+func (m testModule) Name() string { return m.name }
+
+func (m testModule) Init(context.Context, *app.App) error {
+	*m.calls = append(*m.calls, m.name+".init")
+	return nil
+}
+
+func (m testModule) Start(context.Context, *app.App) error {
+	*m.calls = append(*m.calls, m.name+".start")
+	return nil
+}
+
+func (m testModule) Stop(context.Context, *app.App) error {
+	*m.calls = append(*m.calls, m.name+".stop")
+	return nil
+}
+
+func newTestCommands() (*Commands, *bytes.Buffer) {
+	a := app.New()
+	a.SetName("demo")
+	a.SetVersion("1.2.3")
+	a.SetCommit("abcdef0")
+	a.SetBuildTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	a.SetSignals()
+	a.SetConfigLoader(func(context.Context, *app.App) error { return nil })
+
+	buf := new(bytes.Buffer)
+	cmds := New(a)
+	cmds.SetOutput(buf)
+	return cmds, buf
+}
+
+func TestVersion(t *testing.T) {
+	cmds, buf := newTestCommands()
+	if err := cmds.Run(context.Background(), []string{"version"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"demo", "1.2.3", "abcdef0", "2026-01-02T03:04:05"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expect the version output contains '%s', but got '%s'", s, buf.String())
+		}
+	}
+}
+
+func TestHelp(t *testing.T) {
+	cmds, buf := newTestCommands()
+	cmds.Add(&Command{
+		Name:  "config check",
+		Short: "Check the configuration.",
+		Flags: func(fs *flag.FlagSet) { fs.String("file", "", "The config file.") },
+	})
+
+	if err := cmds.Run(context.Background(), []string{"help"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"config check", "Check the configuration.", "help", "version"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expect the help output contains '%s', but got '%s'", s, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := cmds.Run(context.Background(), []string{"help", "config", "check"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"Usage: demo config check [flags]", "-file"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expect the help output contains '%s', but got '%s'", s, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := cmds.Run(context.Background(), []string{"config", "check", "-h"}); err != nil {
+		t.Errorf("expect nil for -h, but got %v", err)
+	} else if !strings.Contains(buf.String(), "-file") {
+		t.Errorf("expect the help output contains the flags, but got '%s'", buf.String())
+	}
+}
+
+func TestRunUnknown(t *testing.T) {
+	cmds, _ := newTestCommands()
+	if err := cmds.Run(context.Background(), nil); err == nil {
+		t.Error("expect an error for the missing command, but got nil")
+	}
+	if err := cmds.Run(context.Background(), []string{"unknown"}); err == nil {
+		t.Error("expect an error for the unknown command, but got nil")
+	}
+}
+
+func TestRunOneShot(t *testing.T) {
+	cmds, _ := newTestCommands()
+
+	var calls []string
+	var dryrun bool
+	var gotArgs []string
+	cmds.Add(&Command{
+		Name:    "migrate",
+		Modules: []app.Module{testModule{name: "db", calls: &calls}},
+		Flags:   func(fs *flag.FlagSet) { fs.BoolVar(&dryrun, "dry-run", false, "") },
+		Run: func(ctx context.Context, a *app.App, args []string) error {
+			calls = append(calls, "migrate")
+			gotArgs = args
+			return nil
+		},
+	})
+
+	if err := cmds.Run(context.Background(), []string{"migrate", "-dry-run", "v2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !dryrun {
+		t.Error("expect the flag dry-run is set, but got not")
+	}
+	if !slices.Equal(gotArgs, []string{"v2"}) {
+		t.Errorf("expect args %v, but got %v", []string{"v2"}, gotArgs)
+	}
+
+	expects := []string{"db.init", "db.start", "migrate", "db.stop"}
+	if !slices.Equal(calls, expects) {
+		t.Errorf("expect calls %v, but got %v", expects, calls)
+	}
+}
+
+func TestRunDefaultConfigLoader(t *testing.T) {
+	defer func(fs *flag.FlagSet, args []string) { flag.CommandLine, os.Args = fs, args }(flag.CommandLine, os.Args)
+	flag.CommandLine = flag.NewFlagSet("demo", flag.ContinueOnError)
+	os.Args = []string{"demo", "-port", "80", "v2"}
+
+	a := app.New()
+	a.SetName("demo")
+	a.SetSignals()
+
+	var port int
+	var gotArgs []string
+	cmds := New(a)
+	cmds.SetOutput(new(bytes.Buffer))
+	cmds.SetDefault("migrate")
+	cmds.Add(&Command{
+		Name:  "migrate",
+		Flags: func(fs *flag.FlagSet) { fs.IntVar(&port, "port", 0, "") },
+		Run: func(ctx context.Context, a *app.App, args []string) error {
+			gotArgs = args
+			return nil
+		},
+	})
+
+	if err := cmds.Run(context.Background(), os.Args[1:]); err != nil {
+		t.Fatal(err)
+	}
+
+	if port != 80 {
+		t.Errorf("expect port %d, but got %d", 80, port)
+	}
+	if !slices.Equal(gotArgs, []string{"v2"}) {
+		t.Errorf("expect args %v, but got %v", []string{"v2"}, gotArgs)
+	}
+}
+
+func TestRunOneShotError(t *testing.T) {
+	cmds, _ := newTestCommands()
+
+	errFail := errors.New("fail")
+	cmds.Add(&Command{
+		Name: "migrate",
+		Run: func(context.Context, *app.App, []string) error {
+			return errFail
+		},
+	})
+
+	if err := cmds.Run(context.Background(), []string{"migrate"}); !errors.Is(err, errFail) {
+		t.Errorf("expect error %v, but got %v", errFail, err)
+	}
+}
+
+func TestRunDefault(t *testing.T) {
+	cmds, _ := newTestCommands()
+	cmds.SetDefault("serve")
+
+	var calls []string
+	var setup bool
+	cmds.Add(&Command{
+		Name:    "serve",
+		Modules: []app.Module{testModule{name: "http", calls: &calls}},
+		Setup: func(a *app.App, fs *flag.FlagSet) error {
+			setup = true
+			a.On(app.StageReady, func(context.Context, *app.App) error {
+				go a.Stop()
+				return nil
+			})
+			return nil
+		},
+	})
+
+	if err := cmds.Run(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if !setup {
+		t.Error("expect Setup is called, but got not")
+	}
+
+	expects := []string{"http.init", "http.start", "http.stop"}
+	if !slices.Equal(calls, expects) {
+		t.Errorf("expect calls %v, but got %v", expects, calls)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xgfone/go-toolkit/app"
+	"github.com/xgfone/go-toolkit/httpx"
+	"github.com/xgfone/go-toolkit/internal/render"
+)
+
+// NewAdminServer returns a new admin server module, which serves the runtime
+// introspection of the app as JSON, separated from the business http server.
+//
+// The endpoints are:
+//
+//	GET /info           the name, version, commit, build time and stage of the app
+//	GET /stage          the current stage and readiness of the app
+//	GET /modules        the registered modules with their states
+//	GET /tasks          the running background tasks
+//	GET /routes         the routes registered by AddRoutes
+//	GET /runtime        the memory and goroutine statistics
+//	    /debug/pprof/   the handlers of net/http/pprof
+//
+// Like NewHttpServer, the admin server is disabled if addr returns "".
+func NewAdminServer(name string, addr func() string) *AdminServer {
+	s := &AdminServer{mux: http.NewServeMux()}
+	s.HttpServer = NewHttpServer(name, addr, nil)
+	return s
+}
+
+// AdminServer is an app module that starts the admin http server.
+type AdminServer struct {
+	*HttpServer
+
+	app    *app.App
+	mux    *http.ServeMux
+	mdws   httpx.Middlewares
+	routes []adminRoutes
+}
+
+type adminRoutes struct {
+	name   string
+	routes func() []httpx.Route
+}
+
+// Use appends the middlewares applied to all the admin endpoints,
+// such as the authentication, which must be called before app runs.
+func (s *AdminServer) Use(mdws ...httpx.Middleware) {
+	s.mdws = append(s.mdws, mdws...)
+}
+
+// AddRoutes registers the routes to be exposed by "GET /routes" with the name,
+// such as router.Router.Routes, which must be called before app runs.
+func (s *AdminServer) AddRoutes(name string, routes func() []httpx.Route) {
+	if routes == nil {
+		panic("AdminServer: routes function must not be nil")
+	}
+	s.routes = append(s.routes, adminRoutes{name: name, routes: routes})
+}
+
+// Handle registers an extra handler for the pattern, which must be called
+// before app runs. The pattern is the same as http.ServeMux.
+func (s *AdminServer) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+func (s *AdminServer) Init(ctx context.Context, a *app.App) (err error) {
+	s.app = a
+
+	s.mux.HandleFunc("GET /info", s.info)
+	s.mux.HandleFunc("GET /stage", s.stage)
+	s.mux.HandleFunc("GET /modules", s.modules)
+	s.mux.HandleFunc("GET /tasks", s.tasks)
+	s.mux.HandleFunc("GET /routes", s.listRoutes)
+	s.mux.HandleFunc("GET /runtime", s.runtime)
+
+	s.mux.HandleFunc("/debug/pprof/", pprof.Index)
+	s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	s.mdws.Sort()
+	s.handler = s.mdws.HTTPHandler(s.mux)
+	return s.HttpServer.Init(ctx, a)
+}
+
+func (s *AdminServer) info(w http.ResponseWriter, r *http.Request) {
+	var buildTime string
+	if t := s.app.BuildTime(); t.Unix() != 0 {
+		buildTime = t.Format(time.RFC3339)
+	}
+
+	_ = render.JSON(w, http.StatusOK, map[string]any{
+		"name":       s.app.Name(),
+		"version":    s.app.Version(),
+		"commit":     s.app.Commit(),
+		"build_time": buildTime,
+		"stage":      s.app.Stage(),
+		"ready":      s.app.Ready(),
+		"pid":        os.Getpid(),
+		"go_version": runtime.Version(),
+	})
+}
+
+func (s *AdminServer) stage(w http.ResponseWriter, r *http.Request) {
+	_ = render.JSON(w, http.StatusOK, map[string]any{"stage": s.app.Stage(), "ready": s.app.Ready()})
+}
+
+func (s *AdminServer) modules(w http.ResponseWriter, r *http.Request) {
+	_ = render.JSON(w, http.StatusOK, s.app.Modules())
+}
+
+func (s *AdminServer) tasks(w http.ResponseWriter, r *http.Request) {
+	_ = render.JSON(w, http.StatusOK, s.app.Tasks())
+}
+
+func (s *AdminServer) listRoutes(w http.ResponseWriter, r *http.Request) {
+	routes := make(map[string][]httpx.Route, len(s.routes))
+	for _, r := range s.routes {
+		routes[r.name] = append(routes[r.name], r.routes()...)
+	}
+	_ = render.JSON(w, http.StatusOK, routes)
+}
+
+func (s *AdminServer) runtime(w http.ResponseWriter, r *http.Request) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	_ = render.JSON(w, http.StatusOK, map[string]any{
+		"goroutines": runtime.NumGoroutine(),
+		"num_cpu":    runtime.NumCPU(),
+		"gomaxprocs": runtime.GOMAXPROCS(0),
+		"memory": map[string]any{
+			"alloc":          stats.Alloc,
+			"total_alloc":    stats.TotalAlloc,
+			"sys":            stats.Sys,
+			"mallocs":        stats.Mallocs,
+			"frees":          stats.Frees,
+			"heap_alloc":     stats.HeapAlloc,
+			"heap_sys":       stats.HeapSys,
+			"heap_idle":      stats.HeapIdle,
+			"heap_inuse":     stats.HeapInuse,
+			"heap_objects":   stats.HeapObjects,
+			"stack_inuse":    stats.StackInuse,
+			"num_gc":         stats.NumGC,
+			"pause_total_ns": stats.PauseTotalNs,
+			"next_gc":        stats.NextGC,
+		},
+	})
+}
+
+// AdminTokenAuth returns a middleware to authenticate the admin requests
+// by the header "Authorization: Bearer <token>", which responds with 401
+// if the token is not one of the given tokens.
+//
+// It panics if no token is given or any token is empty.
+func AdminTokenAuth(tokens ...string) httpx.Middleware {
+	if len(tokens) == 0 {
+		panic("AdminTokenAuth: no token")
+	}
+	if slices.Contains(tokens, "") {
+		panic("AdminTokenAuth: token must not be empty")
+	}
+
+	return httpx.MiddlewareFunc(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if ok && matchToken(tokens, token) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			_ = render.JSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		})
+	})
+}
+
+func matchToken(tokens []string, token string) bool {
+	if token == "" {
+		return false
+	}
+
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xgfone/go-toolkit/app"
+	"github.com/xgfone/go-toolkit/httpx"
+	"github.com/xgfone/go-toolkit/httpx/router"
+)
+
+func serveAdmin(t *testing.T, s *AdminServer, path, token string, v any) int {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	if v != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s: fail to decode the response: %v", path, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestAdminServer(t *testing.T) {
+	a := app.New()
+	a.SetName("demo")
+	a.SetVersion("1.2.3")
+
+	r := router.New()
+	r.Register(httpx.Route{Method: "GET", Path: "/users", Handler: httpx.Handler204})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users", nil))
+
+	s := NewAdminServer("admin", getAddrFunc("127.0.0.1:0"))
+	s.AddRoutes("api", r.Routes)
+	s.Use(AdminTokenAuth("secret"))
+	a.Use(s)
+
+	if err := s.Init(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+	defer s.closeListeners()
+
+	if code := serveAdmin(t, s, "/info", "", nil); code != http.StatusUnauthorized {
+		t.Errorf("expect status code %d without token, but got %d", http.StatusUnauthorized, code)
+	}
+	if code := serveAdmin(t, s, "/info", "wrong", nil); code != http.StatusUnauthorized {
+		t.Errorf("expect status code %d with a wrong token, but got %d", http.StatusUnauthorized, code)
+	}
+
+	var info map[string]any
+	if code := serveAdmin(t, s, "/info", "secret", &info); code != http.StatusOK {
+		t.Fatalf("expect status code %d, but got %d", http.StatusOK, code)
+	}
+	if info["name"] != "demo" || info["version"] != "1.2.3" {
+		t.Errorf("unexpected info: %v", info)
+	}
+
+	var modules []app.ModuleStatus
+	serveAdmin(t, s, "/modules", "secret", &modules)
+	if len(modules) != 1 || modules[0].Name != "admin" || modules[0].State != app.ModulePending {
+		t.Errorf("unexpected modules: %v", modules)
+	}
+
+	var routes map[string][]httpx.Route
+	serveAdmin(t, s, "/routes", "secret", &routes)
+	if rs := routes["api"]; len(rs) != 1 || rs[0].Path != "/users" || !rs[0].Online {
+		t.Errorf("unexpected routes: %v", routes)
+	}
+
+	var stats map[string]any
+	serveAdmin(t, s, "/runtime", "secret", &stats)
+	if n, _ := stats["goroutines"].(float64); n <= 0 {
+		t.Errorf("expect the number of goroutines, but got %v", stats["goroutines"])
+	}
+
+	for _, path := range []string{"/stage", "/tasks", "/debug/pprof/"} {
+		if code := serveAdmin(t, s, path, "secret", nil); code != http.StatusOK {
+			t.Errorf("%s: expect status code %d, but got %d", path, http.StatusOK, code)
+		}
+	}
+}
+
+func TestAdminTokenAuth(t *testing.T) {
+	for _, tokens := range [][]string{nil, {"secret", ""}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%q: expect a panic, but got nil", tokens)
+				}
+			}()
+			AdminTokenAuth(tokens...)
+		}()
+	}
+
+	if matchToken([]string{"secret"}, "") {
+		t.Error("expect the empty token to be rejected")
+	}
+	if !matchToken([]string{"a", "secret"}, "secret") {
+		t.Error("expect the token to be matched")
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is used to calculate the next run time of a job.
+type Schedule interface {
+	// Next returns the next run time strictly after t,
+	// or the zero time if there is no next run.
+	Next(t time.Time) time.Time
+}
+
+// Every returns a schedule that runs at the fixed interval.
+func Every(interval time.Duration) Schedule {
+	if interval <= 0 {
+		panic("Every: the interval must be greater than 0")
+	}
+	return everySchedule(interval)
+}
+
+type everySchedule time.Duration
+
+func (s everySchedule) Next(t time.Time) time.Time { return t.Add(time.Duration(s)) }
+func (s everySchedule) String() string             { return "@every " + time.Duration(s).String() }
+
+// ParseCron parses the standard cron expression with 5 fields,
+// "minute hour day-of-month month day-of-week", to a schedule.
+//
+// Each field supports "*", the value, the range "a-b", the step "*/n" or
+// "a-b/n", and the list separated by ",". The month and the day of week
+// also support the names, such as "JAN" and "MON", and 7 is also Sunday.
+// If both the day of month and the day of week are restricted, a day
+// matching either of them is matched, the same as the standard cron.
+//
+// It also supports the descriptors, "@yearly", "@annually", "@monthly",
+// "@weekly", "@daily", "@midnight", "@hourly" and "@every DURATION".
+func ParseCron(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(expr[len("@every "):]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression '%s': %w", expr, err)
+		} else if interval <= 0 {
+			return nil, fmt.Errorf("invalid cron expression '%s': the interval must be positive", expr)
+		}
+		return everySchedule(interval), nil
+	}
+
+	spec := expr
+	switch expr {
+	case "@yearly", "@annually":
+		spec = "0 0 1 1 *"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@hourly":
+		spec = "0 * * * *"
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression '%s': expect 5 fields, but got %d", expr, len(fields))
+	}
+
+	var s cronSchedule
+	var err error
+	s.expr = expr
+	for i, f := range cronFields {
+		var bits uint64
+		if bits, err = parseCronField(fields[i], f); err != nil {
+			return nil, fmt.Errorf("invalid cron expression '%s': %s field: %w", expr, f.name, err)
+		}
+
+		switch i {
+		case 0:
+			s.minute = bits
+		case 1:
+			s.hour = bits
+		case 2:
+			s.dom, s.domStar = bits, fields[i] == "*" || fields[i] == "?"
+		case 3:
+			s.month = bits
+		case 4:
+			// 7 is also Sunday.
+			s.dow, s.dowStar = bits|(bits>>7&1), fields[i] == "*" || fields[i] == "?"
+		}
+	}
+
+	return s, nil
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{
+		"JAN", "FEB", "MAR", "APR", "MAY", "JUN",
+		"JUL", "AUG", "SEP", "OCT", "NOV", "DEC",
+	}},
+	{name: "day of week", min: 0, max: 7, names: []string{
+		"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT",
+	}},
+}
+
+func parseCronField(field string, f cronField) (bits uint64, err error) {
+	for part := range strings.SplitSeq(field, ",") {
+		rng, stepstr, hasStep := strings.Cut(part, "/")
+
+		start, end := f.min, f.max
+		switch rng {
+		case "*", "?":
+		default:
+			lo, hi, isRange := strings.Cut(rng, "-")
+			if start, err = parseCronValue(lo, f); err != nil {
+				return
+			}
+
+			switch {
+			case isRange:
+				if end, err = parseCronValue(hi, f); err != nil {
+					return
+				}
+			case !hasStep:
+				end = start
+			}
+
+			if start > end {
+				return 0, fmt.Errorf("invalid range '%s'", rng)
+			}
+		}
+
+		step := 1
+		if hasStep {
+			if step, err = strconv.Atoi(stepstr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step '%s'", stepstr)
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return
+}
+
+func parseCronValue(s string, f cronField) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return i + f.min, nil
+		}
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value '%s'", s)
+	}
+	return v, nil
+}
+
+type cronSchedule struct {
+	expr string
+
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	domStar bool
+	dowStar bool
+}
+
+func (s cronSchedule) String() string { return s.expr }
+
+func (s cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// No match within 5 years, such as "0 0 30 2 *".
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s cronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
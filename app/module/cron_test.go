@@ -0,0 +1,64 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	base := time.Date(2026, 1, 15, 10, 30, 20, 0, time.UTC) // Thursday
+	for _, c := range []struct {
+		expr string
+		next time.Time
+	}{
+		{expr: "* * * * *", next: time.Date(2026, 1, 15, 10, 31, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", next: time.Date(2026, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{expr: "0 9-17/4 * * *", next: time.Date(2026, 1, 15, 13, 0, 0, 0, time.UTC)},
+		{expr: "5,10 0 * * *", next: time.Date(2026, 1, 16, 0, 5, 0, 0, time.UTC)},
+		{expr: "0 0 1 * *", next: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * MON", next: time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", next: time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * FRI", next: time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 FEB *", next: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", next: time.Time{}},
+		{expr: "@hourly", next: time.Date(2026, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{expr: "@yearly", next: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "@every 90s", next: base.Add(90 * time.Second)},
+	} {
+		schedule, err := ParseCron(c.expr)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.expr, err)
+			continue
+		}
+
+		if next := schedule.Next(base); !next.Equal(c.next) {
+			t.Errorf("%s: expect next time %s, but got %s", c.expr, c.next, next)
+		}
+	}
+}
+
+func TestParseCronError(t *testing.T) {
+	for _, expr := range []string{
+		"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+		"* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *",
+		"* * * XYZ *", "@every", "@every -1s",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("%s: expect an error, but got nil", expr)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xgfone/go-toolkit/app"
+)
+
+// NewGracefulRestart returns a new GracefulRestart module,
+// which hands the listeners of the given http servers over to the new process.
+//
+// By default, it restarts the process when receiving SIGUSR2 on unix.
+func NewGracefulRestart(name string, servers ...*HttpServer) *GracefulRestart {
+	return &GracefulRestart{
+		name:    name,
+		servers: servers,
+		signal:  defaultRestartSignal,
+		timeout: time.Minute,
+	}
+}
+
+// GracefulRestart is an app module to restart the process without downtime.
+//
+// When restarting, it re-executes the program with the listening sockets of
+// the http servers as the inherited file descriptors, then waits for the new
+// process to be ready. The http server in the new process adopts the listener
+// inherited by the same name, and the new process notifies the old one when
+// the app reaches StageReady, then the old process drains and exits.
+type GracefulRestart struct {
+	name    string
+	servers []*HttpServer
+	signal  os.Signal
+	timeout time.Duration
+	pidfile *PidFile
+
+	lock sync.Mutex
+	path string
+	args []string
+
+	output io.Writer // Only for test
+}
+
+// SetSignal resets the signal to trigger the restart, which must be called
+// before app runs. If sig is nil, no signal is listened to and the restart
+// can only be triggered by calling Restart.
+func (g *GracefulRestart) SetSignal(sig os.Signal) {
+	g.signal = sig
+}
+
+// SetPidFile sets the pidfile module whose lock is handed over to the new
+// process, so that the new process can pass Init of the same pidfile module
+// while the old process is still running. It must be called before app runs.
+func (g *GracefulRestart) SetPidFile(p *PidFile) {
+	g.pidfile = p
+}
+
+// SetReadyTimeout resets the timeout to wait for the new process to be ready.
+//
+// Default: 1m
+func (g *GracefulRestart) SetReadyTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		panic("GracefulRestart: the ready timeout must be greater than 0")
+	}
+	g.timeout = timeout
+}
+
+// SetCommand resets the program path and the arguments to start the new process.
+//
+// Default: the current executable with os.Args[1:].
+func (g *GracefulRestart) SetCommand(path string, args ...string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.path, g.args = path, args
+}
+
+func (g *GracefulRestart) Name() string {
+	return g.name
+}
+
+func (g *GracefulRestart) Init(ctx context.Context, a *app.App) (err error) {
+	a.OnNamed(app.StageReady, g.name, func(context.Context, *app.App) error {
+		return notifyInheritedReady()
+	})
+	return
+}
+
+func (g *GracefulRestart) Start(ctx context.Context, a *app.App) (err error) {
+	if g.signal == nil {
+		return
+	}
+
+	sigch := make(chan os.Signal, 1)
+	signal.Notify(sigch, g.signal)
+	a.GoNamed(g.name, func(ctx context.Context) error {
+		defer signal.Stop(sigch)
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+
+			case <-sigch:
+				if err := g.Restart(ctx); err != nil {
+					slog.Error("fail to restart the process gracefully", "modname", g.name, "err", err)
+					continue
+				}
+
+				slog.Info("the new process is ready, and stop the old", "modname", g.name)
+				a.Stop()
+				return nil
+			}
+		}
+	})
+
+	return
+}
+
+func (g *GracefulRestart) Stop(context.Context, *app.App) (err error) {
+	return
+}
+
+// Restart starts a new process which inherits the listeners of the http
+// servers, and waits until it is ready.
+//
+// If returning nil, the caller should stop the current process, such as app.Stop.
+// Or, the new process is killed if it is not ready.
+func (g *GracefulRestart) Restart(ctx context.Context) (err error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	names := make([]string, 0, len(g.servers))
+	lns := make([]net.Listener, 0, len(g.servers))
+	files := make([]*os.File, 0, len(g.servers)+1)
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+
+	for _, s := range g.servers {
+		if !s.IsValid() {
+			continue
+		}
+
+		for _, l := range s.lns {
+			file, err := listenerFile(l.rawln)
+			if err != nil {
+				return fmt.Errorf("fail to get the listener file of http server '%s': %w", s.name, err)
+			}
+
+			names = append(names, l.name)
+			lns = append(lns, l.rawln)
+			files = append(files, file)
+		}
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return
+	}
+	defer r.Close()
+	files = append(files, w)
+
+	path, args := g.path, g.args
+	if path == "" {
+		if path, err = os.Executable(); err != nil {
+			return
+		}
+		args = os.Args[1:]
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if g.output != nil {
+		cmd.Stdout, cmd.Stderr = g.output, g.output
+	}
+	cmd.ExtraFiles = files
+	cmd.Env = append(environ(EnvInheritListeners, EnvInheritReadyFD, EnvInheritLockFD,
+		"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"),
+		EnvInheritListeners+"="+strings.Join(names, ","),
+		EnvInheritReadyFD+"="+strconv.Itoa(3+len(names)),
+	)
+
+	// The locked file is shared with the new process, but not closed here.
+	if g.pidfile != nil && g.pidfile.lockfile != nil {
+		cmd.ExtraFiles = append(files[:len(files):len(files)], g.pidfile.lockfile)
+		cmd.Env = append(cmd.Env, EnvInheritLockFD+"="+strconv.Itoa(3+len(files)))
+	}
+
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("fail to start the new process: %w", err)
+	}
+
+	// Close the write end in the current process so that reading it
+	// returns EOF when the new process exits before being ready.
+	_ = w.Close()
+	files = files[:len(files)-1]
+
+	readych := make(chan error, 1)
+	go func() {
+		var buf [1]byte
+		_, err := io.ReadFull(r, buf[:])
+		if errors.Is(err, io.EOF) {
+			err = errors.New("the new process exited before being ready")
+		}
+		readych <- err
+	}()
+
+	timer := time.NewTimer(g.timeout)
+	defer timer.Stop()
+
+	select {
+	case err = <-readych:
+	case <-timer.C:
+		err = fmt.Errorf("the new process is not ready within %s", g.timeout)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		_ = cmd.Process.Kill()
+		go cmd.Wait() // Reap the killed process.
+		return
+	}
+
+	// The socket files are now used by the new process, so the current one
+	// must not remove them when stopping.
+	for _, ln := range lns {
+		keepSocketFile(ln)
+	}
+
+	slog.Info("the new process is ready", "modname", g.name, "pid", cmd.Process.Pid)
+	return cmd.Process.Release()
+}
+
+// environ returns the environment variables of the current process
+// excluding the given keys.
+func environ(excludes ...string) []string {
+	envs := os.Environ()
+	result := make([]string, 0, len(envs))
+	for _, env := range envs {
+		key, _, _ := strings.Cut(env, "=")
+		if !slices.Contains(excludes, key) {
+			result = append(result, env)
+		}
+	}
+	return result
+}
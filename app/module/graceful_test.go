@@ -0,0 +1,248 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-toolkit/app"
+)
+
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func textHandler(text string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, text)
+	})
+}
+
+func httpGet(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("fail to request %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("fail to read the response body: %v", err)
+	}
+	return string(data)
+}
+
+// TestGracefulRestartChild is run in the new process started by TestGracefulRestart.
+func TestGracefulRestartChild(t *testing.T) {
+	if os.Getenv(EnvInheritListeners) == "" {
+		t.Skip("only run in the process started by TestGracefulRestart")
+	}
+
+	a := app.New()
+	a.SetSignals()
+	a.SetReloadSignals()
+
+	mux := http.NewServeMux()
+	mux.Handle("/", textHandler("child"))
+	mux.HandleFunc("/exit", func(w http.ResponseWriter, r *http.Request) { a.Stop() })
+
+	server := NewHttpServer("graceful", getAddrFunc("127.0.0.1:0"), mux)
+	restart := NewGracefulRestart("restart", server)
+	restart.SetSignal(nil)
+	a.Use(server, restart)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	if err := a.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGracefulRestart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("inheriting the listeners is not supported on windows")
+	}
+
+	server := NewHttpServer("graceful", getAddrFunc("127.0.0.1:0"), textHandler("parent"))
+	restart := NewGracefulRestart("restart", server)
+	restart.SetSignal(nil)
+	restart.SetReadyTimeout(time.Second * 10)
+	restart.SetCommand(os.Args[0], "-test.run=^TestGracefulRestartChild$")
+	restart.output = new(syncBuffer)
+
+	a := app.New()
+	a.SetSignals()
+	a.SetReloadSignals()
+	a.Use(server, restart)
+
+	errch := make(chan error, 1)
+	go func() { errch <- a.Run(context.Background()) }()
+	for !a.Ready() {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	url := "http://" + server.Addrs()[0].String()
+	if body := httpGet(t, url); body != "parent" {
+		t.Errorf("expect response '%s', but got '%s'", "parent", body)
+	}
+
+	if err := restart.Restart(context.Background()); err != nil {
+		t.Fatalf("fail to restart: %v, output: %s", err, restart.output)
+	}
+
+	a.Stop()
+	if err := <-errch; err != nil {
+		t.Fatalf("fail to stop the old app: %v", err)
+	}
+
+	// The old process has stopped, so the requests are served by the new one
+	// on the same listening socket.
+	http.DefaultClient.CloseIdleConnections()
+	if body := httpGet(t, url); body != "child" {
+		t.Errorf("expect response '%s', but got '%s'", "child", body)
+	}
+	_ = httpGet(t, url+"/exit")
+}
+
+func TestGracefulRestartNotReady(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("inheriting the listeners is not supported on windows")
+	}
+
+	sockpath := filepath.Join(t.TempDir(), "http.sock")
+	server := NewHttpServer("graceful", getAddrFunc("127.0.0.1:0"), textHandler("parent"))
+	server.AddAddr(getAddrFunc("unix://" + sockpath))
+	if err := server.Init(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	defer server.closeListeners()
+
+	restart := NewGracefulRestart("restart", server)
+	restart.SetCommand(os.Args[0], "-test.run=^$")
+	restart.output = io.Discard
+	if err := restart.Restart(context.Background()); err == nil {
+		t.Errorf("expect an error when the new process exits before being ready, but got nil")
+	}
+
+	// The current process keeps running, so it still owns the socket file.
+	server.closeListeners()
+	if _, err := os.Stat(sockpath); !os.IsNotExist(err) {
+		t.Errorf("expect the socket file to be removed, but got %v", err)
+	}
+}
+
+// TestGracefulRestartPidFileChild is run in the new process started by TestGracefulRestartPidFile.
+func TestGracefulRestartPidFileChild(t *testing.T) {
+	path := os.Getenv("TEST_GRACEFUL_PIDFILE")
+	if os.Getenv(EnvInheritListeners) == "" || path == "" {
+		t.Skip("only run in the process started by TestGracefulRestartPidFile")
+	}
+
+	a := app.New()
+	a.SetSignals()
+	a.SetReloadSignals()
+
+	mux := http.NewServeMux()
+	mux.Handle("/", textHandler("child"))
+	mux.HandleFunc("/exit", func(w http.ResponseWriter, r *http.Request) { a.Stop() })
+
+	pidfile := NewPidFile("pidfile", path)
+	server := NewHttpServer("graceful", getAddrFunc("127.0.0.1:0"), mux)
+	restart := NewGracefulRestart("restart", server)
+	restart.SetSignal(nil)
+	restart.SetPidFile(pidfile)
+	a.Use(pidfile, server, restart)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	if err := a.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGracefulRestartPidFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("inheriting the listeners is not supported on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "app.pid")
+	t.Setenv("TEST_GRACEFUL_PIDFILE", path)
+
+	pidfile := NewPidFile("pidfile", path)
+	server := NewHttpServer("graceful", getAddrFunc("127.0.0.1:0"), textHandler("parent"))
+	restart := NewGracefulRestart("restart", server)
+	restart.SetSignal(nil)
+	restart.SetPidFile(pidfile)
+	restart.SetReadyTimeout(time.Second * 10)
+	restart.SetCommand(os.Args[0], "-test.run=^TestGracefulRestartPidFileChild$")
+	restart.output = new(syncBuffer)
+
+	a := app.New()
+	a.SetSignals()
+	a.SetReloadSignals()
+	a.Use(pidfile, server, restart)
+
+	errch := make(chan error, 1)
+	go func() { errch <- a.Run(context.Background()) }()
+	for !a.Ready() {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	if err := restart.Restart(context.Background()); err != nil {
+		t.Fatalf("fail to restart: %v, output: %s", err, restart.output)
+	}
+
+	a.Stop()
+	if err := <-errch; err != nil {
+		t.Fatalf("fail to stop the old app: %v", err)
+	}
+
+	// The pidfile has been rewritten by the new process and kept by the old one.
+	if pid, err := readPidFile(path); err != nil || pid <= 0 || pid == os.Getpid() {
+		t.Errorf("expect the pid of the new process, but got %d, err=%v", pid, err)
+	}
+
+	// The lock is still held by the new process after the old one exits.
+	err := NewPidFile("pidfile", path).Init(context.Background(), app.New())
+	if err == nil || !strings.Contains(err.Error(), "another instance is running") {
+		t.Errorf("expect the lock held by the new process, but got %v", err)
+	}
+
+	http.DefaultClient.CloseIdleConnections()
+	_ = httpGet(t, "http://"+server.Addrs()[0].String()+"/exit")
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package module
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+var defaultRestartSignal os.Signal = syscall.SIGUSR2
+
+// listenerFile returns a duplicated file of the listener to pass to the child.
+func listenerFile(ln net.Listener) (*os.File, error) {
+	filer, ok := ln.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, fmt.Errorf("unsupported listener %T", ln)
+	}
+	return filer.File()
+}
+
+// keepSocketFile keeps the socket file of the unix listener when closing it,
+// which must be called only after the child has inherited it and been ready.
+func keepSocketFile(ln net.Listener) {
+	if uln, ok := ln.(*net.UnixListener); ok {
+		uln.SetUnlinkOnClose(false)
+	}
+}
@@ -16,10 +16,13 @@ package module
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 	"time"
 
@@ -30,83 +33,329 @@ import (
 //
 // The addr function is called during Init to get the listen address. If addr is
 // nil or returns an empty string, the HTTP server is disabled and won't start.
+// More addresses may be added by AddAddr, which are served together.
+//
+// The address may be "HOST:PORT" or "tcp://HOST:PORT" for TCP, and
+// "unix:///PATH" or "unix://RELATIVE_PATH" for the unix socket.
+//
+// If systemd passes a listener named as the server name by socket activation,
+// it is used instead of listening on the address. The address "systemd://NAME"
+// requires the listener named NAME in LISTEN_FDNAMES, and "systemd://" uses
+// the first one passed by systemd.
 func NewHttpServer(name string, addr func() string, handler http.Handler) *HttpServer {
-	return &HttpServer{name: name, getAddr: addr, handler: handler}
+	// Keep the nil addr as the index 0 to name the listeners of AddAddr.
+	return &HttpServer{
+		name:     name,
+		handler:  handler,
+		unixUID:  -1,
+		unixGID:  -1,
+		conns:    newConnTracker(),
+		getAddrs: []func() string{addr},
+	}
 }
 
 // HttpServer is an app module that starts an HTTP server.
+//
+// It serves HTTPS if TLS is enabled by SetTLS. All the listeners share
+// the same http.Server, so Stop drains them together.
 type HttpServer struct {
 	name string
-	addr string
+	addr string // The address of the first listener.
+
+	getAddrs []func() string
+	getOpts  func() HttpServerOptions
+	getTLS   func() TLSOptions
+	conns    *connTracker
+	grace    time.Duration
+	tls      *tlsReloader
+	h2c      bool
+
+	unixMode os.FileMode
+	unixUID  int
+	unixGID  int
 
-	getAddr func() string
 	handler http.Handler
 	server  *http.Server
-	listen  net.Listener
+	lns     []*httpListener
 	wrapln  func(net.Listener) net.Listener
 }
 
-// WrapListener registers wrap to replace the listener created by Init,
-// which must be called before app runs.
+type httpListener struct {
+	name   string       // The name to inherit the listener.
+	listen net.Listener // The listener served by the http server.
+	rawln  net.Listener // The original listener before being wrapped.
+}
+
+// WrapListener registers wrap to replace the listeners created by Init,
+// which must be called before app runs, such as Wrap of the package
+// netx/proxyproto to parse the PROXY protocol header.
 func (s *HttpServer) WrapListener(wrap func(net.Listener) net.Listener) {
 	s.wrapln = wrap
 }
 
+// AddAddr adds an extra listen address, which must be called before app runs.
+// If addr returns an empty string during Init, it is ignored.
+//
+// The listener of the extra address is inherited by the name "NAME#INDEX",
+// such as "api#1", where NAME is the server name and INDEX is the index
+// of the address starting from 0 for the address passed to NewHttpServer.
+func (s *HttpServer) AddAddr(addr func() string) {
+	if addr == nil {
+		panic("HttpServer: addr function must not be nil")
+	}
+	s.getAddrs = append(s.getAddrs, addr)
+}
+
+// SetOptions resets the options returned by the function,
+// which is called during Init and must be set before app runs.
+func (s *HttpServer) SetOptions(options func() HttpServerOptions) {
+	s.getOpts = options
+}
+
+// ConnStats returns the statistics of the connections.
+func (s *HttpServer) ConnStats() HttpConnStats {
+	return s.conns.Stats()
+}
+
+// SetUnixSocketMode resets the file mode of the unix socket files,
+// which must be called before app runs.
+//
+// Default: 0, which keeps the mode created by the umask.
+func (s *HttpServer) SetUnixSocketMode(mode os.FileMode) {
+	s.unixMode = mode
+}
+
+// SetUnixSocketOwner resets the owner and group of the unix socket files,
+// which must be called before app runs. -1 keeps the id unchanged.
+//
+// Default: -1, -1
+func (s *HttpServer) SetUnixSocketOwner(uid, gid int) {
+	s.unixUID, s.unixGID = uid, gid
+}
+
+// SetH2C enables or disables the cleartext HTTP/2 with prior knowledge
+// on the listeners without TLS, which must be called before app runs.
+//
+// Default: false
+func (s *HttpServer) SetH2C(enabled bool) {
+	s.h2c = enabled
+}
+
 // IsValid reports whether the http server is valid.
 func (s *HttpServer) IsValid() bool {
 	return s.addr != ""
 }
 
+// Addrs returns the actual addresses of all the listeners after Init,
+// such as the port allocated for ":0".
+func (s *HttpServer) Addrs() []net.Addr {
+	addrs := make([]net.Addr, len(s.lns))
+	for i, l := range s.lns {
+		addrs[i] = l.rawln.Addr()
+	}
+	return addrs
+}
+
 func (s *HttpServer) Name() string {
 	return s.name
 }
 
 func (s *HttpServer) Init(ctx context.Context, a *app.App) (err error) {
-	if s.getAddr != nil {
-		s.addr = s.getAddr()
+	var addrs []string
+	var indexes []int // The registration indexes of addrs to name the listeners.
+	for i, getAddr := range s.getAddrs {
+		if getAddr == nil {
+			continue
+		}
+		if addr := getAddr(); addr != "" {
+			addrs = append(addrs, addr)
+			indexes = append(indexes, i)
+		}
 	}
 
-	if s.addr == "" {
+	if len(addrs) == 0 {
 		return
 	}
 
-	network := "tcp"
-	if strings.Contains(s.addr, "://") {
-		if u, err := url.Parse(s.addr); err == nil && u.Scheme != "" {
-			network = u.Scheme
-			s.addr = u.Host
+	defer func() {
+		if err != nil {
+			s.closeListeners()
 		}
+	}()
+
+	var opts HttpServerOptions
+	if s.getOpts != nil {
+		opts = s.getOpts()
 	}
 
-	s.listen, err = net.Listen(network, s.addr)
-	if err != nil {
-		return
+	var limiter connLimiter
+	if opts.MaxConns > 0 {
+		limiter = newConnLimiter(opts.MaxConns)
+	}
+
+	if s.getTLS != nil {
+		if opts := s.getTLS(); opts.CertFile != "" {
+			if s.tls, err = newTLSReloader(opts); err != nil {
+				return
+			}
+		}
 	}
 
-	if s.wrapln != nil {
-		s.listen = s.wrapln(s.listen)
+	for i, addr := range addrs {
+		l := &httpListener{name: s.name}
+		if indexes[i] > 0 && s.name != "" {
+			l.name = fmt.Sprintf("%s#%d", s.name, indexes[i])
+		}
+
+		if l.rawln, err = s.listenAddr(l.name, addr); err != nil {
+			return
+		}
+		s.lns = append(s.lns, l)
+
+		l.listen = l.rawln
+		if limiter != nil {
+			l.listen = limiter.Wrap(l.listen)
+		}
+		if s.wrapln != nil {
+			l.listen = s.wrapln(l.listen)
+		}
+		if s.tls != nil {
+			l.listen = tls.NewListener(l.listen, s.tls.TLSConfig())
+		}
 	}
 
+	s.grace = opts.ShutdownGracePeriod
+	s.addr = s.lns[0].rawln.Addr().String()
 	s.server = &http.Server{
 		Addr:    s.addr,
 		Handler: s.handler,
 
-		IdleTimeout:       time.Minute * 3,
-		ReadHeaderTimeout: time.Second * 3,
+		ReadTimeout:       opts.ReadTimeout,
+		WriteTimeout:      opts.WriteTimeout,
+		IdleTimeout:       getTimeout(opts.IdleTimeout, time.Minute*3),
+		ReadHeaderTimeout: getTimeout(opts.ReadHeaderTimeout, time.Second*3),
+		MaxHeaderBytes:    opts.MaxHeaderBytes,
+
+		ConnState: s.conns.ConnState,
+		ErrorLog:  slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
+	}
 
-		ErrorLog: slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
+	if s.h2c {
+		s.server.Protocols = new(http.Protocols)
+		s.server.Protocols.SetHTTP1(true)
+		s.server.Protocols.SetHTTP2(true)
+		s.server.Protocols.SetUnencryptedHTTP2(true)
 	}
 
 	return
 }
 
+func (s *HttpServer) listenAddr(name, addr string) (ln net.Listener, err error) {
+	network := "tcp"
+	if strings.Contains(addr, "://") {
+		if u, err := url.Parse(addr); err == nil && u.Scheme != "" {
+			network = u.Scheme
+			addr = u.Host
+			if network == "unix" {
+				addr += u.Path
+			}
+		}
+	}
+
+	// Adopt the listener inherited from the parent process by GracefulRestart,
+	// or passed by systemd socket activation, with the same name.
+	if name != "" {
+		if ln = takeInheritedListener(name); ln != nil {
+			return
+		}
+	}
+
+	switch network {
+	case "systemd":
+		// The address "systemd://NAME" selects the listener by LISTEN_FDNAMES,
+		// and "systemd://" selects the first one not taken.
+		if ln = takeInheritedListener(addr); ln == nil {
+			err = fmt.Errorf("no systemd listener named '%s'", addr)
+		}
+
+	case "unix":
+		ln, err = s.listenUnix(addr)
+
+	default:
+		ln, err = net.Listen(network, addr)
+	}
+
+	return
+}
+
+func (s *HttpServer) listenUnix(path string) (ln net.Listener, err error) {
+	removeStaleUnixSocket(path)
+	if ln, err = net.Listen("unix", path); err != nil {
+		return
+	}
+
+	if s.unixMode != 0 {
+		err = os.Chmod(path, s.unixMode)
+	}
+	if err == nil && (s.unixUID >= 0 || s.unixGID >= 0) {
+		err = os.Chown(path, s.unixUID, s.unixGID)
+	}
+
+	if err != nil {
+		_ = ln.Close() // The socket file is removed when closing.
+		return nil, err
+	}
+	return
+}
+
+// removeStaleUnixSocket removes the socket file left by the crashed process,
+// on which nobody listens, so that listening on it does not fail.
+func removeStaleUnixSocket(path string) {
+	fi, err := os.Lstat(path)
+	if err != nil || fi.Mode().Type() != os.ModeSocket {
+		return
+	}
+
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		_ = conn.Close()
+		return
+	}
+
+	slog.Warn("remove the stale unix socket", "path", path)
+	_ = os.Remove(path)
+}
+
+func (s *HttpServer) closeListeners() {
+	for _, l := range s.lns {
+		_ = l.listen.Close()
+	}
+	s.lns = nil
+}
+
 func (s *HttpServer) Start(context.Context, *app.App) (err error) {
 	if !s.IsValid() {
 		return
 	}
 
-	slog.Info("start the http server", "modname", s.name, "addr", s.addr)
-	go s.server.Serve(s.listen)
+	slog.Info("start the http server", "modname", s.name, "addrs", s.Addrs(), "tls", s.tls != nil)
+	if s.tls != nil {
+		s.tls.start(s.name)
+	}
+
+	for _, l := range s.lns {
+		go s.server.Serve(l.listen)
+	}
+	return
+}
+
+// Reload implements the interface app.Reloader to reload the TLS certificate
+// files unconditionally if TLS is enabled.
+func (s *HttpServer) Reload(context.Context, *app.App) (err error) {
+	if s.tls != nil {
+		if err = s.tls.Reload(); err == nil {
+			slog.Info("reload the tls certificate", "modname", s.name, "certfile", s.tls.opts.CertFile)
+		}
+	}
 	return
 }
 
@@ -115,6 +364,26 @@ func (s *HttpServer) Stop(ctx context.Context, app *app.App) (err error) {
 		return
 	}
 
-	slog.Info("stop the http server", "modname", s.name, "addr", s.addr)
-	return s.server.Shutdown(ctx)
+	slog.Info("stop the http server", "modname", s.name, "addrs", s.Addrs())
+	if s.tls != nil {
+		s.tls.close()
+	}
+
+	sctx := ctx
+	if s.grace > 0 {
+		var cancel context.CancelFunc
+		sctx, cancel = context.WithTimeout(ctx, s.grace)
+		defer cancel()
+	}
+
+	if err = s.server.Shutdown(sctx); err != nil && sctx.Err() != nil {
+		slog.Warn("force to close the remaining connections of the http server",
+			"modname", s.name, "conns", s.conns.Stats())
+
+		_ = s.server.Close()
+		if ctx.Err() == nil {
+			err = nil // Only the grace period expires.
+		}
+	}
+	return
 }
@@ -0,0 +1,187 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HttpServerOptions is the options of HttpServer.
+//
+// For the timeouts, see http.Server.
+type HttpServerOptions struct {
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// Default: 3s. A negative value disables it.
+	ReadHeaderTimeout time.Duration
+
+	// Default: 3m. A negative value disables it.
+	IdleTimeout time.Duration
+
+	// Default: http.DefaultMaxHeaderBytes
+	MaxHeaderBytes int
+
+	// MaxConns is the maximum number of the concurrent connections
+	// of all the listeners. When reaching it, the new connections are
+	// not accepted until some connections are closed.
+	//
+	// Default: 0, which means no limit.
+	MaxConns int
+
+	// ShutdownGracePeriod is the maximum duration for Stop to wait for
+	// the active connections to become idle, then all the remaining
+	// connections are closed forcibly. They are also closed forcibly
+	// when the context passed to Stop is done.
+	//
+	// Default: 0, which means that only the context of Stop is used.
+	ShutdownGracePeriod time.Duration
+}
+
+// getTimeout returns _default if v is 0, or 0 if v is negative.
+func getTimeout(v, _default time.Duration) time.Duration {
+	switch {
+	case v < 0:
+		return 0
+	case v == 0:
+		return _default
+	default:
+		return v
+	}
+}
+
+// HttpConnStats is the statistics of the connections of HttpServer.
+type HttpConnStats struct {
+	// New, Active and Idle are the numbers of the connections
+	// in the corresponding states of http.ConnState.
+	New    int `json:"new"`
+	Active int `json:"active"`
+	Idle   int `json:"idle"`
+
+	// Hijacked is the total number of the hijacked connections,
+	// which are no longer tracked by the server, such as websocket.
+	Hijacked uint64 `json:"hijacked"`
+
+	// Accepted is the total number of the accepted connections.
+	Accepted uint64 `json:"accepted"`
+}
+
+// connTracker tracks the states of the connections by http.Server.ConnState.
+type connTracker struct {
+	lock  sync.Mutex
+	conns map[net.Conn]http.ConnState
+	stats HttpConnStats
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{conns: make(map[net.Conn]http.ConnState, 16)}
+}
+
+func (t *connTracker) Stats() HttpConnStats {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.stats
+}
+
+func (t *connTracker) ConnState(c net.Conn, state http.ConnState) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if old, ok := t.conns[c]; ok {
+		t.counter(old, -1)
+	} else if state == http.StateNew {
+		t.stats.Accepted++
+	}
+
+	switch state {
+	case http.StateNew, http.StateActive, http.StateIdle:
+		t.conns[c] = state
+		t.counter(state, 1)
+
+	case http.StateHijacked:
+		delete(t.conns, c)
+		t.stats.Hijacked++
+
+	case http.StateClosed:
+		delete(t.conns, c)
+	}
+}
+
+func (t *connTracker) counter(state http.ConnState, delta int) {
+	switch state {
+	case http.StateNew:
+		t.stats.New += delta
+	case http.StateActive:
+		t.stats.Active += delta
+	case http.StateIdle:
+		t.stats.Idle += delta
+	}
+}
+
+// connLimiter limits the number of the concurrent connections
+// accepted by a group of the listeners.
+type connLimiter chan struct{}
+
+func newConnLimiter(max int) connLimiter {
+	return make(connLimiter, max)
+}
+
+func (l connLimiter) Wrap(ln net.Listener) net.Listener {
+	return &limitListener{Listener: ln, limiter: l, done: make(chan struct{})}
+}
+
+type limitListener struct {
+	net.Listener
+	limiter connLimiter
+
+	once sync.Once
+	done chan struct{}
+}
+
+func (l *limitListener) Accept() (net.Conn, error) {
+	select {
+	case l.limiter <- struct{}{}:
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+
+	c, err := l.Listener.Accept()
+	if err != nil {
+		<-l.limiter
+		return nil, err
+	}
+
+	return &limitConn{Conn: c, release: func() { <-l.limiter }}, nil
+}
+
+func (l *limitListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return l.Listener.Close()
+}
+
+type limitConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *limitConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
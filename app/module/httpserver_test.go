@@ -16,10 +16,16 @@ package module
 
 import (
 	"context"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 type wrappedListener struct{ net.Listener }
@@ -91,12 +97,12 @@ func TestHttpServerWrapListener(t *testing.T) {
 	if err := m.Init(context.Background(), nil); err != nil {
 		t.Fatalf("Init: unexpected error: %v", err)
 	}
-	defer m.listen.Close()
+	defer m.closeListeners()
 
 	if captured == nil {
 		t.Fatal("WrapListener was not called")
 	}
-	if m.listen != wrapped {
+	if m.lns[0].listen != wrapped {
 		t.Fatal("Init did not use the wrapped listener")
 	}
 }
@@ -119,7 +125,7 @@ func TestHttpServerInvalidAddr(t *testing.T) {
 			if m.IsValid() {
 				t.Fatal("server should be invalid")
 			}
-			if m.listen != nil || m.server != nil {
+			if len(m.lns) != 0 || m.server != nil {
 				t.Fatal("invalid server should not create a listener or server")
 			}
 			if err := m.Start(context.Background(), nil); err != nil {
@@ -156,3 +162,257 @@ func TestHttpServerURLScheme(t *testing.T) {
 		t.Errorf("Start returned error: %v", err)
 	}
 }
+
+func TestHttpServerMultipleListeners(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the unix socket file mode is not supported on windows")
+	}
+
+	sockpath := filepath.Join(t.TempDir(), "http.sock")
+
+	// Simulate the stale socket file left by the crashed process.
+	stale, err := net.Listen("unix", sockpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(interface{ SetUnlinkOnClose(bool) }).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	})
+
+	m := NewHttpServer("multi", getAddrFunc("127.0.0.1:0"), handler)
+	m.AddAddr(getAddrFunc(""))
+	m.AddAddr(getAddrFunc("unix://" + sockpath))
+	m.SetUnixSocketMode(0600)
+	m.SetH2C(true)
+
+	ctx := context.Background()
+	if err := m.Init(ctx, nil); err != nil {
+		t.Fatalf("Init: unexpected error: %v", err)
+	}
+	if err := m.Start(ctx, nil); err != nil {
+		t.Fatalf("Start: unexpected error: %v", err)
+	}
+
+	addrs := m.Addrs()
+	if len(addrs) != 2 {
+		t.Fatalf("expect %d addresses, but got %v", 2, addrs)
+	}
+	if addr := addrs[0].(*net.TCPAddr); addr.Port == 0 {
+		t.Errorf("expect the allocated port, but got %s", addr)
+	}
+	if addr := addrs[1].String(); addr != sockpath {
+		t.Errorf("expect unix socket '%s', but got '%s'", sockpath, addr)
+	}
+	if names := []string{m.lns[0].name, m.lns[1].name}; names[0] != "multi" || names[1] != "multi#2" {
+		t.Errorf("unexpected listener names: %v", names)
+	}
+
+	if fi, err := os.Stat(sockpath); err != nil {
+		t.Error(err)
+	} else if mode := fi.Mode().Perm(); mode != 0600 {
+		t.Errorf("expect socket file mode %o, but got %o", 0600, mode)
+	}
+
+	h2c := &http.Protocols{}
+	h2c.SetUnencryptedHTTP2(true)
+	tcpClient := &http.Client{Transport: &http.Transport{Protocols: h2c}}
+	unixClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, "unix", sockpath)
+		},
+	}}
+
+	for _, c := range []struct {
+		client *http.Client
+		url    string
+		proto  string
+	}{
+		{client: tcpClient, url: "http://" + addrs[0].String(), proto: "HTTP/2.0"},
+		{client: unixClient, url: "http://unix", proto: "HTTP/1.1"},
+	} {
+		resp, err := c.client.Get(c.url)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.url, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		c.client.CloseIdleConnections()
+
+		if string(body) != c.proto {
+			t.Errorf("%s: expect protocol '%s', but got '%s'", c.url, c.proto, body)
+		}
+	}
+
+	if err := m.Stop(ctx, nil); err != nil {
+		t.Errorf("Stop: unexpected error: %v", err)
+	}
+	if _, err := os.Stat(sockpath); !os.IsNotExist(err) {
+		t.Errorf("expect the socket file to be removed, but got %v", err)
+	}
+}
+
+func TestHttpServerUnixSocketInUse(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the unix socket is not fully supported on windows")
+	}
+
+	sockpath := filepath.Join(t.TempDir(), "http.sock")
+	holder, err := net.Listen("unix", sockpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Close()
+
+	m := NewHttpServer("inuse", getAddrFunc("unix://"+sockpath), http.NotFoundHandler())
+	if err := m.Init(context.Background(), nil); err == nil {
+		m.closeListeners()
+		t.Error("expect Init to fail on the socket in use, but got nil")
+	}
+}
+
+func TestHttpServerOptions(t *testing.T) {
+	m := NewHttpServer("options", getAddrFunc("127.0.0.1:0"), http.NotFoundHandler())
+	m.SetOptions(func() HttpServerOptions {
+		return HttpServerOptions{
+			ReadTimeout:       time.Second,
+			WriteTimeout:      time.Second * 2,
+			ReadHeaderTimeout: -1,
+			MaxHeaderBytes:    1024,
+		}
+	})
+
+	if err := m.Init(context.Background(), nil); err != nil {
+		t.Fatalf("Init: unexpected error: %v", err)
+	}
+	defer m.closeListeners()
+
+	if v := m.server.ReadTimeout; v != time.Second {
+		t.Errorf("expect ReadTimeout %s, but got %s", time.Second, v)
+	}
+	if v := m.server.WriteTimeout; v != time.Second*2 {
+		t.Errorf("expect WriteTimeout %s, but got %s", time.Second*2, v)
+	}
+	if v := m.server.ReadHeaderTimeout; v != 0 {
+		t.Errorf("expect no ReadHeaderTimeout, but got %s", v)
+	}
+	if v := m.server.IdleTimeout; v != time.Minute*3 {
+		t.Errorf("expect the default IdleTimeout %s, but got %s", time.Minute*3, v)
+	}
+	if v := m.server.MaxHeaderBytes; v != 1024 {
+		t.Errorf("expect MaxHeaderBytes %d, but got %d", 1024, v)
+	}
+}
+
+// waitConnStats waits until the connection statistics match.
+func waitConnStats(t *testing.T, m *HttpServer, match func(HttpConnStats) bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second * 5); !match(m.ConnStats()); {
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected connection stats: %+v", m.ConnStats())
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+}
+
+func TestHttpServerMaxConns(t *testing.T) {
+	block := make(chan struct{})
+	m := NewHttpServer("maxconns", getAddrFunc("127.0.0.1:0"), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/block" {
+			<-block
+		}
+	}))
+	m.SetOptions(func() HttpServerOptions { return HttpServerOptions{MaxConns: 1} })
+
+	ctx := context.Background()
+	if err := m.Init(ctx, nil); err != nil {
+		t.Fatalf("Init: unexpected error: %v", err)
+	}
+	if err := m.Start(ctx, nil); err != nil {
+		t.Fatalf("Start: unexpected error: %v", err)
+	}
+	defer m.Stop(ctx, nil)
+
+	addr := m.Addrs()[0].String()
+	sendRequest := func(path string) net.Conn {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.WriteString(conn, "GET "+path+" HTTP/1.1\r\nHost: test\r\nConnection: close\r\n\r\n")
+		return conn
+	}
+
+	conn1 := sendRequest("/block")
+	defer conn1.Close()
+	waitConnStats(t, m, func(s HttpConnStats) bool { return s.Active == 1 })
+
+	// The second connection is not accepted while the first is active.
+	conn2 := sendRequest("/")
+	defer conn2.Close()
+	_ = conn2.SetReadDeadline(time.Now().Add(time.Millisecond * 200))
+	if _, err := conn2.Read(make([]byte, 1)); err == nil {
+		t.Fatal("expect the second connection to wait, but it is served")
+	}
+
+	close(block)
+	_ = conn2.SetReadDeadline(time.Now().Add(time.Second * 5))
+	if data, err := io.ReadAll(conn2); err != nil {
+		t.Fatalf("fail to read the response: %v", err)
+	} else if !strings.HasPrefix(string(data), "HTTP/1.1 200") {
+		t.Errorf("unexpected response: %s", data)
+	}
+
+	waitConnStats(t, m, func(s HttpConnStats) bool {
+		return s.Accepted == 2 && s.New == 0 && s.Active == 0 && s.Idle == 0
+	})
+}
+
+func TestHttpServerShutdownGracePeriod(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	m := NewHttpServer("grace", getAddrFunc("127.0.0.1:0"), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	m.SetOptions(func() HttpServerOptions {
+		return HttpServerOptions{ShutdownGracePeriod: time.Millisecond * 100}
+	})
+
+	ctx := context.Background()
+	if err := m.Init(ctx, nil); err != nil {
+		t.Fatalf("Init: unexpected error: %v", err)
+	}
+	if err := m.Start(ctx, nil); err != nil {
+		t.Fatalf("Start: unexpected error: %v", err)
+	}
+
+	errch := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + m.Addrs()[0].String())
+		if err == nil {
+			resp.Body.Close()
+		}
+		errch <- err
+	}()
+	waitConnStats(t, m, func(s HttpConnStats) bool { return s.Active == 1 })
+
+	start := time.Now()
+	if err := m.Stop(ctx, nil); err != nil {
+		t.Errorf("Stop: unexpected error: %v", err)
+	}
+	if cost := time.Since(start); cost > time.Second {
+		t.Errorf("expect Stop to return after the grace period, but took %s", cost)
+	}
+
+	select {
+	case err := <-errch:
+		if err == nil {
+			t.Error("expect the active connection to be closed forcibly, but got nil")
+		}
+	case <-time.After(time.Second * 5):
+		t.Error("the active connection is not closed")
+	}
+}
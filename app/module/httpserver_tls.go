@@ -0,0 +1,224 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTLSReloadInterval is the default interval to check
+// whether the certificate files have changed.
+var DefaultTLSReloadInterval = time.Second * 10
+
+// TLSOptions is the TLS options of HttpServer.
+type TLSOptions struct {
+	// CertFile and KeyFile are the paths of the PEM-encoded certificate
+	// chain and private key. If CertFile is empty, TLS is disabled.
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile is the path of the PEM-encoded CA bundle to verify
+	// the client certificates. If set, the mutual TLS is enabled.
+	ClientCAFile string
+
+	// ClientAuth is the policy of the client certificate authentication.
+	//
+	// Default: tls.RequireAndVerifyClientCert if ClientCAFile is set.
+	ClientAuth tls.ClientAuthType
+
+	// MinVersion is the minimum TLS version.
+	//
+	// Default: tls.VersionTLS12
+	MinVersion uint16
+
+	// ReloadInterval is the interval to check whether the files have changed,
+	// which are reloaded if so. A negative value disables the check.
+	//
+	// Default: DefaultTLSReloadInterval
+	ReloadInterval time.Duration
+}
+
+// SetTLS enables TLS with the options returned by the function,
+// which is called during Init and must be set before app runs.
+//
+// The certificate, the key and the client CA bundle are reloaded without
+// restarting the server when the files change, or when the app reloads.
+// The failure of reloading is logged and the old ones are kept.
+//
+// For the mutual TLS, use the middleware ClientCert of the package
+// httpx/middleware to expose the client identity to the handlers.
+func (s *HttpServer) SetTLS(options func() TLSOptions) {
+	s.getTLS = options
+}
+
+// tlsReloader holds the TLS config loaded from the files,
+// and reloads it when the files change.
+type tlsReloader struct {
+	opts   TLSOptions
+	config atomic.Pointer[tls.Config]
+
+	lock  sync.Mutex
+	stamp string
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func newTLSReloader(opts TLSOptions) (*tlsReloader, error) {
+	if opts.KeyFile == "" {
+		return nil, fmt.Errorf("missing the TLS key file")
+	}
+	if opts.ClientCAFile != "" && opts.ClientAuth == tls.NoClientCert {
+		opts.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	if opts.MinVersion == 0 {
+		opts.MinVersion = tls.VersionTLS12
+	}
+	if opts.ReloadInterval == 0 {
+		opts.ReloadInterval = DefaultTLSReloadInterval
+	}
+
+	r := &tlsReloader{opts: opts}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig returns the TLS config used by the listener, which delegates
+// to the latest loaded config for each new connection.
+func (r *tlsReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: r.opts.MinVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.config.Load(), nil
+		},
+	}
+}
+
+// Reload reloads the files unconditionally.
+func (r *tlsReloader) Reload() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.stamp = r.getStamp()
+	return r.load()
+}
+
+// ReloadIfChanged reloads the files only if they have changed.
+func (r *tlsReloader) ReloadIfChanged() (reloaded bool, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// Record the stamp even if failing to load, which avoids retrying
+	// the broken files repeatedly until they change again.
+	stamp := r.getStamp()
+	if stamp == r.stamp {
+		return
+	}
+
+	r.stamp = stamp
+	return true, r.load()
+}
+
+func (r *tlsReloader) getStamp() string {
+	var b strings.Builder
+	for _, path := range [...]string{r.opts.CertFile, r.opts.KeyFile, r.opts.ClientCAFile} {
+		if path != "" {
+			if fi, err := os.Stat(path); err == nil {
+				fmt.Fprintf(&b, "%d:%d;", fi.ModTime().UnixNano(), fi.Size())
+			} else {
+				b.WriteString("-;")
+			}
+		}
+	}
+	return b.String()
+}
+
+func (r *tlsReloader) load() (err error) {
+	cert, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("fail to load the TLS certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   r.opts.MinVersion,
+		NextProtos:   []string{"h2", "http/1.1"},
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   r.opts.ClientAuth,
+	}
+
+	if r.opts.ClientCAFile != "" {
+		data, err := os.ReadFile(r.opts.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("fail to load the TLS client CA: %w", err)
+		}
+
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(data) {
+			return fmt.Errorf("no valid certificate in the TLS client CA file '%s'", r.opts.ClientCAFile)
+		}
+	}
+
+	r.config.Store(config)
+	return
+}
+
+func (r *tlsReloader) start(name string) {
+	if r.opts.ReloadInterval < 0 {
+		return
+	}
+
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.watch(name, r.stop, r.done)
+}
+
+// close stops the watcher and waits until it exits.
+func (r *tlsReloader) close() {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+		r.stop, r.done = nil, nil
+	}
+}
+
+func (r *tlsReloader) watch(name string, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(r.opts.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+
+		case <-ticker.C:
+			switch reloaded, err := r.ReloadIfChanged(); {
+			case err != nil:
+				slog.Error("fail to reload the tls certificate", "modname", name, "err", err)
+			case reloaded:
+				slog.Info("reload the tls certificate", "modname", name, "certfile", r.opts.CertFile)
+			}
+		}
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-toolkit/httpx"
+	"github.com/xgfone/go-toolkit/httpx/middleware"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+// newTestCert generates a certificate signed by parent,
+// or a self-signed CA certificate if parent is nil.
+func newTestCert(t *testing.T, cn string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+
+	signer, signkey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signkey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (c *testCert) write(t *testing.T, certfile, keyfile string) {
+	t.Helper()
+
+	keyder, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certpem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der})
+	if err = os.WriteFile(certfile, certpem, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if keyfile != "" {
+		keypem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyder})
+		if err = os.WriteFile(keyfile, keypem, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func (c *testCert) tlsCert() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key}
+}
+
+func startTLSServer(t *testing.T, opts TLSOptions, handler http.Handler) *HttpServer {
+	t.Helper()
+
+	s := NewHttpServer("tls", getAddrFunc("127.0.0.1:0"), handler)
+	s.SetTLS(func() TLSOptions { return opts })
+	if err := s.Init(context.Background(), nil); err != nil {
+		t.Fatalf("Init: unexpected error: %v", err)
+	}
+	if err := s.Start(context.Background(), nil); err != nil {
+		t.Fatalf("Start: unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Stop(context.Background(), nil) })
+	return s
+}
+
+// getPeerCN returns the common name of the server certificate by a new connection.
+func getPeerCN(t *testing.T, addr string, config *tls.Config) string {
+	t.Helper()
+
+	conn, err := tls.Dial("tcp", addr, config)
+	if err != nil {
+		t.Fatalf("fail to dial: %v", err)
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+}
+
+func TestHttpServerTLSReload(t *testing.T) {
+	dir := t.TempDir()
+	certfile := filepath.Join(dir, "cert.pem")
+	keyfile := filepath.Join(dir, "key.pem")
+
+	ca := newTestCert(t, "ca", nil)
+	newTestCert(t, "server1", ca).write(t, certfile, keyfile)
+
+	opts := TLSOptions{CertFile: certfile, KeyFile: keyfile, ReloadInterval: time.Millisecond * 10}
+	s := startTLSServer(t, opts, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	}))
+	addr := s.Addrs()[0].String()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	config := &tls.Config{RootCAs: roots}
+
+	if cn := getPeerCN(t, addr, config); cn != "server1" {
+		t.Errorf("expect certificate '%s', but got '%s'", "server1", cn)
+	}
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: config, ForceAttemptHTTP2: true}}
+	resp, err := client.Get("https://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "HTTP/2.0" {
+		t.Errorf("expect protocol '%s', but got '%s'", "HTTP/2.0", body)
+	}
+
+	t.Run("watch", func(t *testing.T) {
+		newTestCert(t, "server2", ca).write(t, certfile, keyfile)
+		future := time.Now().Add(time.Minute)
+		_ = os.Chtimes(certfile, future, future)
+
+		deadline := time.Now().Add(time.Second * 5)
+		for getPeerCN(t, addr, config) != "server2" {
+			if time.Now().After(deadline) {
+				t.Fatal("the certificate is not reloaded after the files change")
+			}
+			time.Sleep(time.Millisecond * 10)
+		}
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		s.tls.close() // Disable the watcher to test Reload only.
+		newTestCert(t, "server3", ca).write(t, certfile, keyfile)
+
+		if cn := getPeerCN(t, addr, config); cn != "server2" {
+			t.Errorf("expect certificate '%s', but got '%s'", "server2", cn)
+		}
+		if err := s.Reload(context.Background(), nil); err != nil {
+			t.Fatalf("Reload: unexpected error: %v", err)
+		}
+		if cn := getPeerCN(t, addr, config); cn != "server3" {
+			t.Errorf("expect certificate '%s', but got '%s'", "server3", cn)
+		}
+	})
+
+	t.Run("broken", func(t *testing.T) {
+		if err := os.WriteFile(keyfile, []byte("invalid"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Reload(context.Background(), nil); err == nil {
+			t.Error("expect an error for the broken key, but got nil")
+		}
+		if cn := getPeerCN(t, addr, config); cn != "server3" {
+			t.Errorf("expect the old certificate '%s', but got '%s'", "server3", cn)
+		}
+	})
+}
+
+func TestHttpServerMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certfile := filepath.Join(dir, "cert.pem")
+	keyfile := filepath.Join(dir, "key.pem")
+	cafile := filepath.Join(dir, "ca.pem")
+
+	ca := newTestCert(t, "ca", nil)
+	ca.write(t, cafile, "")
+	newTestCert(t, "server", ca).write(t, certfile, keyfile)
+
+	handler := middleware.Context(middleware.ClientCert(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := httpx.GetContext(r.Context()).Auth.(*middleware.ClientIdentity); ok {
+			_, _ = io.WriteString(w, id.CommonName)
+		}
+	})))
+
+	opts := TLSOptions{CertFile: certfile, KeyFile: keyfile, ClientCAFile: cafile, ReloadInterval: -1}
+	s := startTLSServer(t, opts, handler)
+	url := "https://" + s.Addrs()[0].String()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	get := func(certs ...tls.Certificate) (string, error) {
+		config := &tls.Config{RootCAs: roots, Certificates: certs}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+		defer client.CloseIdleConnections()
+
+		resp, err := client.Get(url)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	if _, err := get(); err == nil {
+		t.Error("expect an error without the client certificate, but got nil")
+	}
+
+	if _, err := get(newTestCert(t, "other", newTestCert(t, "otherca", nil)).tlsCert()); err == nil {
+		t.Error("expect an error with the untrusted client certificate, but got nil")
+	}
+
+	if cn, err := get(newTestCert(t, "client", ca).tlsCert()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if cn != "client" {
+		t.Errorf("expect client identity '%s', but got '%s'", "client", cn)
+	}
+}
+
+func TestHttpServerTLSInitFail(t *testing.T) {
+	s := NewHttpServer("tls", getAddrFunc("127.0.0.1:0"), http.NotFoundHandler())
+	s.SetTLS(func() TLSOptions {
+		return TLSOptions{CertFile: "/nonexistent/cert.pem", KeyFile: "/nonexistent/key.pem"}
+	})
+	if err := s.Init(context.Background(), nil); err == nil {
+		t.Error("expect Init to fail with the missing certificate, but got nil")
+	}
+}
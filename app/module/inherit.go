@@ -0,0 +1,201 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The environment variables passed to the child process by GracefulRestart.
+const (
+	// EnvInheritListeners is the comma-separated names of the inherited
+	// listeners, whose file descriptors start from 3 in order.
+	EnvInheritListeners = "GO_TOOLKIT_INHERIT_LISTENERS"
+
+	// EnvInheritReadyFD is the file descriptor that the child process writes
+	// to notify the parent process that it is ready.
+	EnvInheritReadyFD = "GO_TOOLKIT_INHERIT_READY_FD"
+
+	// EnvInheritLockFD is the file descriptor of the locked file
+	// of the pidfile, which is handed over to the child process.
+	EnvInheritLockFD = "GO_TOOLKIT_INHERIT_LOCK_FD"
+)
+
+// The first file descriptor passed by systemd socket activation.
+const sdListenFDsStart = 3
+
+type inheritedListener struct {
+	name string
+	ln   net.Listener
+}
+
+var inherited struct {
+	once      sync.Once
+	lock      sync.Mutex
+	listeners []inheritedListener
+	ready     *os.File
+	lockfile  *os.File
+}
+
+// loadInherited loads the listeners and the ready notifier inherited from
+// the parent process, or the listeners passed by systemd socket activation,
+// only once, then removes the environment variables so that they are not
+// inherited by the grandchild processes.
+func loadInherited() {
+	inherited.once.Do(func() {
+		inherited.listeners = loadSystemdListeners(os.Getenv, sdListenFDsStart)
+		_ = os.Unsetenv("LISTEN_PID")
+		_ = os.Unsetenv("LISTEN_FDS")
+		_ = os.Unsetenv("LISTEN_FDNAMES")
+
+		names := os.Getenv(EnvInheritListeners)
+		readyfd := os.Getenv(EnvInheritReadyFD)
+		lockfd := os.Getenv(EnvInheritLockFD)
+		_ = os.Unsetenv(EnvInheritListeners)
+		_ = os.Unsetenv(EnvInheritReadyFD)
+		_ = os.Unsetenv(EnvInheritLockFD)
+
+		if names != "" && len(inherited.listeners) == 0 {
+			for i, name := range strings.Split(names, ",") {
+				if ln := fileListener(uintptr(3+i), name); ln != nil {
+					inherited.listeners = append(inherited.listeners, inheritedListener{name: name, ln: ln})
+				}
+			}
+		}
+
+		if fd, err := strconv.ParseUint(readyfd, 10, 32); err == nil {
+			inherited.ready = os.NewFile(uintptr(fd), "ready")
+		}
+		if fd, err := strconv.ParseUint(lockfd, 10, 32); err == nil {
+			inherited.lockfile = os.NewFile(uintptr(fd), "lock")
+		}
+	})
+}
+
+// loadSystemdListeners loads the listeners passed by systemd socket activation
+// by LISTEN_PID, LISTEN_FDS and LISTEN_FDNAMES, the file descriptors of which
+// start from start.
+func loadSystemdListeners(getenv func(string) string, start int) (listeners []inheritedListener) {
+	if getenv("LISTEN_PID") != strconv.Itoa(os.Getpid()) {
+		return
+	}
+
+	nfds, err := strconv.Atoi(getenv("LISTEN_FDS"))
+	if err != nil || nfds <= 0 {
+		return
+	}
+
+	var names []string
+	if fdnames := getenv("LISTEN_FDNAMES"); fdnames != "" {
+		names = strings.Split(fdnames, ":")
+	}
+
+	listeners = make([]inheritedListener, 0, nfds)
+	for i := range nfds {
+		name := "unknown" // Same as the default of systemd.
+		if i < len(names) {
+			name = names[i]
+		}
+
+		if ln := fileListener(uintptr(start+i), name); ln != nil {
+			listeners = append(listeners, inheritedListener{name: name, ln: ln})
+		}
+	}
+
+	return
+}
+
+func fileListener(fd uintptr, name string) net.Listener {
+	file := os.NewFile(fd, name)
+	ln, err := net.FileListener(file)
+	_ = file.Close()
+	if err != nil {
+		slog.Error("fail to inherit the listener", "name", name, "fd", fd, "err", err)
+		return nil
+	}
+	return ln
+}
+
+// takeInheritedListener returns the listener named name inherited from
+// the parent process or systemd, which is returned only once.
+// If name is empty, return the first one not taken.
+//
+// Return nil if no listener is inherited with the name.
+func takeInheritedListener(name string) net.Listener {
+	loadInherited()
+
+	inherited.lock.Lock()
+	defer inherited.lock.Unlock()
+
+	for i, l := range inherited.listeners {
+		if name == "" || l.name == name {
+			inherited.listeners = append(inherited.listeners[:i], inherited.listeners[i+1:]...)
+			return l.ln
+		}
+	}
+	return nil
+}
+
+// takeInheritedLockFile returns the locked file of the pidfile inherited
+// from the parent process if it refers to path, which is returned only once.
+//
+// Return nil if no locked file is inherited for path.
+func takeInheritedLockFile(path string) *os.File {
+	loadInherited()
+
+	inherited.lock.Lock()
+	file := inherited.lockfile
+	inherited.lockfile = nil
+	inherited.lock.Unlock()
+
+	if file == nil {
+		return nil
+	}
+
+	fi1, err1 := os.Stat(path)
+	fi2, err2 := file.Stat()
+	if err1 != nil || err2 != nil || !os.SameFile(fi1, fi2) {
+		_ = file.Close()
+		return nil
+	}
+	return file
+}
+
+// notifyInheritedReady notifies the parent process that this process is ready
+// if it is started by GracefulRestart. It is a no-op for the second call.
+func notifyInheritedReady() error {
+	loadInherited()
+
+	inherited.lock.Lock()
+	ready := inherited.ready
+	inherited.ready = nil
+	inherited.lock.Unlock()
+
+	if ready == nil {
+		return nil
+	}
+
+	defer ready.Close()
+	if _, err := ready.Write([]byte{'1'}); err != nil {
+		return fmt.Errorf("fail to notify the parent process: %w", err)
+	}
+	return nil
+}
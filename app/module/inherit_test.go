@@ -0,0 +1,148 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package module
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+// dupListenerFD returns a duplicated file descriptor of a new tcp listener,
+// which is closed by the function loading it.
+func dupListenerFD(t *testing.T) (fd int, addr string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	file, err := ln.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if fd, err = syscall.Dup(int(file.Fd())); err != nil {
+		t.Fatal(err)
+	}
+	return fd, ln.Addr().String()
+}
+
+func TestLoadSystemdListeners(t *testing.T) {
+	fd, addr := dupListenerFD(t)
+	env := map[string]string{
+		"LISTEN_PID":     strconv.Itoa(os.Getpid()),
+		"LISTEN_FDS":     "1",
+		"LISTEN_FDNAMES": "web",
+	}
+
+	listeners := loadSystemdListeners(func(key string) string { return env[key] }, fd)
+	if len(listeners) != 1 {
+		t.Fatalf("expect 1 listener, but got %d", len(listeners))
+	}
+	defer listeners[0].ln.Close()
+
+	if name := listeners[0].name; name != "web" {
+		t.Errorf("expect listener name '%s', but got '%s'", "web", name)
+	}
+	if got := listeners[0].ln.Addr().String(); got != addr {
+		t.Errorf("expect listener addr '%s', but got '%s'", addr, got)
+	}
+
+	env["LISTEN_PID"] = strconv.Itoa(os.Getpid() + 1)
+	if listeners := loadSystemdListeners(func(key string) string { return env[key] }, fd); len(listeners) != 0 {
+		t.Errorf("expect no listeners for another process, but got %d", len(listeners))
+	}
+}
+
+func TestHttpServerSystemdListener(t *testing.T) {
+	loadInherited()
+
+	addListener := func(name string) string {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		inherited.lock.Lock()
+		inherited.listeners = append(inherited.listeners, inheritedListener{name: name, ln: ln})
+		inherited.lock.Unlock()
+		return ln.Addr().String()
+	}
+
+	for _, c := range []struct {
+		name string
+		addr string
+	}{
+		{name: "api", addr: "systemd://web"},
+		{name: "web", addr: "127.0.0.1:0"},
+		{name: "admin", addr: "systemd://"},
+	} {
+		want := addListener("web")
+		s := NewHttpServer(c.name, getAddrFunc(c.addr), nil)
+		if err := s.Init(context.Background(), nil); err != nil {
+			t.Fatalf("%s: Init: unexpected error: %v", c.name, err)
+		}
+
+		if got := s.Addrs()[0].String(); got != want {
+			t.Errorf("%s: expect addr '%s', but got '%s'", c.name, want, got)
+		}
+		s.closeListeners()
+	}
+
+	s := NewHttpServer("api", getAddrFunc("systemd://web"), nil)
+	if err := s.Init(context.Background(), nil); err == nil {
+		t.Error("expect an error without the systemd listener, but got nil")
+	}
+}
+
+func TestHttpServerInheritedListenerIndex(t *testing.T) {
+	loadInherited()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// The listener inherited for the disabled primary address.
+	inherited.lock.Lock()
+	inherited.listeners = append(inherited.listeners, inheritedListener{name: "index", ln: ln})
+	inherited.lock.Unlock()
+	defer takeInheritedListener("index")
+
+	s := NewHttpServer("index", getAddrFunc(""), nil)
+	s.AddAddr(getAddrFunc("127.0.0.1:0"))
+	if err := s.Init(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	defer s.closeListeners()
+
+	if len(s.lns) != 1 {
+		t.Fatalf("expect 1 listener, but got %d", len(s.lns))
+	}
+	if name := s.lns[0].name; name != "index#1" {
+		t.Errorf("expect listener name '%s', but got '%s'", "index#1", name)
+	}
+	if addr := s.Addrs()[0].String(); addr == ln.Addr().String() {
+		t.Errorf("the extra address should not adopt the listener of the primary address")
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/xgfone/go-toolkit/app"
+	"github.com/xgfone/go-toolkit/internal/flock"
+)
+
+// NewPidFile returns a new pidfile module to guard the single running
+// instance, which writes the pid into the file path and holds the advisory
+// lock of the file path+".lock" for the whole lifetime of the process.
+//
+// If path is empty, the pidfile module is disabled.
+func NewPidFile(name, path string) *PidFile {
+	return &PidFile{name: name, path: path, lockpath: path + ".lock"}
+}
+
+// PidFile is an app module to write the pidfile and guard the single instance.
+//
+// Init fails if another instance holds the lock. Since the lock is released
+// by the operating system when the process exits, the pidfile left by the
+// crashed process is stale and reclaimed if the lock is acquired.
+// The pidfile is removed in StageCleanup if it still records the current
+// process, but the lock file is kept so that all the instances always lock
+// the same file.
+//
+// To restart the process by GracefulRestart, register the pidfile by
+// GracefulRestart.SetPidFile, so that the lock is handed over to the new
+// process, which then rewrites the pidfile with its own pid.
+type PidFile struct {
+	name     string
+	path     string
+	lockpath string
+	lockfile *os.File
+}
+
+// SetLockFile resets the path of the lock file, which must be called before app runs.
+//
+// Default: the pidfile path with the suffix ".lock".
+func (p *PidFile) SetLockFile(path string) {
+	if path == "" {
+		panic("PidFile: the lock file path must not be empty")
+	}
+	p.lockpath = path
+}
+
+func (p *PidFile) Name() string {
+	return p.name
+}
+
+func (p *PidFile) Init(ctx context.Context, a *app.App) (err error) {
+	if p.path == "" {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(p.lockpath), 0755); err != nil {
+		return
+	}
+
+	// The lock is shared with the inherited file handed over by the parent
+	// process, so locking it again succeeds even if the parent is running.
+	lockfile := takeInheritedLockFile(p.lockpath)
+	if lockfile == nil {
+		if lockfile, err = os.OpenFile(p.lockpath, os.O_RDWR|os.O_CREATE, 0644); err != nil {
+			return
+		}
+	}
+
+	if err = flock.TryLock(lockfile); err != nil {
+		_ = lockfile.Close()
+		if errors.Is(err, flock.ErrLocked) {
+			if pid, _ := readPidFile(p.path); pid > 0 {
+				return fmt.Errorf("another instance is running with pid %d, which holds the lock file '%s'", pid, p.lockpath)
+			}
+			return fmt.Errorf("another instance is running, which holds the lock file '%s'", p.lockpath)
+		}
+		return fmt.Errorf("fail to lock the file '%s': %w", p.lockpath, err)
+	}
+
+	if pid, _ := readPidFile(p.path); pid > 0 && pid != os.Getpid() {
+		slog.Warn("reclaim the stale pidfile", "modname", p.name, "pidfile", p.path, "pid", pid)
+	}
+
+	if err = writePidFile(p.path, os.Getpid()); err != nil {
+		_ = lockfile.Close()
+		return fmt.Errorf("fail to write the pidfile '%s': %w", p.path, err)
+	}
+
+	p.lockfile = lockfile
+	a.OnNamed(app.StageCleanup, p.name, app.CloserFuncHook(p.cleanup))
+	return
+}
+
+func (p *PidFile) Start(context.Context, *app.App) (err error) {
+	return
+}
+
+func (p *PidFile) Stop(context.Context, *app.App) (err error) {
+	return
+}
+
+func (p *PidFile) cleanup() (err error) {
+	if p.lockfile == nil {
+		return
+	}
+
+	// The pidfile may have been rewritten by the new process
+	// started by GracefulRestart, which must be kept.
+	if pid, _ := readPidFile(p.path); pid == os.Getpid() {
+		if err = os.Remove(p.path); errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	}
+
+	err = errors.Join(err, p.lockfile.Close())
+	p.lockfile = nil
+	return
+}
+
+func readPidFile(path string) (pid int, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	return strconv.Atoi(string(bytes.TrimSpace(data)))
+}
+
+// writePidFile writes the pid into a temporary file then renames it,
+// so that the readers never see a partial pidfile.
+func writePidFile(path string, pid int) (err error) {
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, []byte(strconv.Itoa(pid)+"\n"), 0644); err != nil {
+		return
+	}
+
+	if err = os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+	}
+	return
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xgfone/go-toolkit/app"
+)
+
+func TestPidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "app.pid")
+
+	p1 := NewPidFile("pidfile", path)
+	if err := p1.Init(context.Background(), app.New()); errors.Is(err, errors.ErrUnsupported) {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatalf("Init: unexpected error: %v", err)
+	}
+
+	if pid, err := readPidFile(path); err != nil || pid != os.Getpid() {
+		t.Errorf("expect pid %d, but got %d, err=%v", os.Getpid(), pid, err)
+	}
+
+	p2 := NewPidFile("pidfile", path)
+	err := p2.Init(context.Background(), app.New())
+	if err == nil || !strings.Contains(err.Error(), strconv.Itoa(os.Getpid())) {
+		t.Errorf("expect an error with the running pid, but got %v", err)
+	}
+
+	if err := p1.cleanup(); err != nil {
+		t.Fatalf("cleanup: unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expect the pidfile is removed, but got %v", err)
+	}
+
+	if err := p2.Init(context.Background(), app.New()); err != nil {
+		t.Errorf("expect nil after the lock is released, but got %v", err)
+	}
+	_ = p2.cleanup()
+}
+
+func TestPidFileStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.pid")
+	if err := os.WriteFile(path, []byte("999999999\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewPidFile("pidfile", path)
+	if err := p.Init(context.Background(), app.New()); errors.Is(err, errors.ErrUnsupported) {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatalf("expect the stale pidfile is reclaimed, but got %v", err)
+	}
+	defer p.cleanup()
+
+	if pid, err := readPidFile(path); err != nil || pid != os.Getpid() {
+		t.Errorf("expect pid %d, but got %d, err=%v", os.Getpid(), pid, err)
+	}
+}
+
+func TestPidFileDisabled(t *testing.T) {
+	p := NewPidFile("pidfile", "")
+	if err := p.Init(context.Background(), app.New()); err != nil {
+		t.Errorf("expect nil, but got %v", err)
+	}
+}
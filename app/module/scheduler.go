@@ -0,0 +1,315 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xgfone/go-toolkit/app"
+	"github.com/xgfone/go-toolkit/runtimex"
+	"github.com/xgfone/go-toolkit/timex"
+)
+
+// Clock is the time source of the scheduler, which may be replaced in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// DefaultClock uses timex.Now and time.After.
+var DefaultClock Clock = defaultClock{}
+
+type defaultClock struct{}
+
+func (defaultClock) Now() time.Time                         { return timex.Now() }
+func (defaultClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// JobOption is used to configure the job.
+type JobOption func(*job)
+
+// SkipIfRunning returns a job option to skip the run
+// if the previous run of the job has not finished.
+func SkipIfRunning() JobOption {
+	return func(j *job) { j.skipOverlap = true }
+}
+
+// JobStats is the run statistics of a scheduled job.
+type JobStats struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule,omitempty"`
+
+	Runs     int64 `json:"runs"`
+	Skips    int64 `json:"skips"`
+	Failures int64 `json:"failures"`
+	Running  int   `json:"running"`
+
+	NextRun      time.Time     `json:"next_run,omitzero"`
+	LastRun      time.Time     `json:"last_run,omitzero"`
+	LastDuration time.Duration `json:"last_duration"`
+	LastError    string        `json:"last_error,omitempty"`
+}
+
+type job struct {
+	name     string
+	schedule Schedule
+	run      func(context.Context) error
+
+	skipOverlap bool
+	stats       JobStats
+}
+
+// NewScheduler returns a new scheduler module to run the jobs periodically.
+func NewScheduler(name string) *Scheduler {
+	return &Scheduler{name: name, clock: DefaultClock}
+}
+
+// Scheduler is an app module to run the jobs by the cron expressions
+// or the fixed intervals.
+//
+// The jobs run under the run context of the app, which is canceled when
+// the app starts to shut down, and Stop waits for the running jobs to finish.
+// The panic of a job is recovered and recorded as its error.
+type Scheduler struct {
+	name  string
+	clock Clock
+
+	lock    sync.Mutex
+	jobs    []*job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// SetClock resets the time source, which must be called before app runs.
+//
+// Default: DefaultClock
+func (s *Scheduler) SetClock(clock Clock) {
+	if clock == nil {
+		panic("Scheduler: clock must not be nil")
+	}
+	s.clock = clock
+}
+
+// AddCron is a convenience method to add the job by the cron expression.
+// See ParseCron.
+func (s *Scheduler) AddCron(name, expr string, run func(context.Context) error, opts ...JobOption) error {
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		return err
+	}
+
+	s.AddJob(name, schedule, run, opts...)
+	return nil
+}
+
+// AddInterval is a convenience method to add the job run at the fixed interval.
+func (s *Scheduler) AddInterval(name string, interval time.Duration, run func(context.Context) error, opts ...JobOption) {
+	s.AddJob(name, Every(interval), run, opts...)
+}
+
+// AddJob adds the job with the schedule, which may be called at any time.
+//
+// If the scheduler has started, the job is scheduled immediately.
+func (s *Scheduler) AddJob(name string, schedule Schedule, run func(context.Context) error, opts ...JobOption) {
+	if schedule == nil {
+		panic("Scheduler: schedule must not be nil")
+	}
+	if run == nil {
+		panic("Scheduler: job function must not be nil")
+	}
+
+	j := &job{name: name, schedule: schedule, run: run}
+	j.stats.Name = name
+	if s, ok := schedule.(fmt.Stringer); ok {
+		j.stats.Schedule = s.String()
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if slices.ContainsFunc(s.jobs, func(j *job) bool { return j.name == name }) {
+		panic(fmt.Sprintf("Scheduler: job '%s' has been added", name))
+	}
+
+	s.jobs = append(s.jobs, j)
+	if s.started {
+		s.startJob(j)
+	}
+}
+
+// Jobs returns the statistics of all the jobs sorted by the name.
+func (s *Scheduler) Jobs() []JobStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := make([]JobStats, len(s.jobs))
+	for i, j := range s.jobs {
+		stats[i] = j.stats
+	}
+
+	slices.SortFunc(stats, func(a, b JobStats) int { return strings.Compare(a.Name, b.Name) })
+	return stats
+}
+
+func (s *Scheduler) Name() string {
+	return s.name
+}
+
+func (s *Scheduler) Init(context.Context, *app.App) (err error) {
+	return
+}
+
+func (s *Scheduler) Start(_ context.Context, a *app.App) (err error) {
+	started := make(chan struct{})
+	a.GoNamed(s.name, func(ctx context.Context) error {
+		s.lock.Lock()
+		s.ctx, s.cancel = context.WithCancel(ctx)
+		s.started = true
+		for _, j := range s.jobs {
+			s.startJob(j)
+		}
+		ctx = s.ctx
+		s.lock.Unlock()
+		close(started)
+
+		<-ctx.Done()
+		return nil
+	})
+
+	<-started
+	return
+}
+
+func (s *Scheduler) Stop(ctx context.Context, _ *app.App) (err error) {
+	s.lock.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() { s.wg.Wait(); close(done) }()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("wait for the running jobs: %w", ctx.Err())
+	}
+	return
+}
+
+// startJob starts the loop of the job, which must be called with the lock.
+func (s *Scheduler) startJob(j *job) {
+	s.wg.Add(1)
+	go func(ctx context.Context) {
+		defer s.wg.Done()
+		s.loop(ctx, j)
+	}(s.ctx)
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	now := s.clock.Now()
+	for {
+		next := j.schedule.Next(now)
+		if next.IsZero() {
+			return
+		}
+
+		s.lock.Lock()
+		j.stats.NextRun = next
+		s.lock.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(next.Sub(now)):
+		}
+
+		// Avoid running the job twice if the clock fires a little early.
+		if now = s.clock.Now(); now.Before(next) {
+			now = next
+		}
+
+		s.lock.Lock()
+		if ctx.Err() != nil { // Stopped after the timer fired.
+			s.lock.Unlock()
+			return
+		}
+		if j.skipOverlap && j.stats.Running > 0 {
+			j.stats.Skips++
+			s.lock.Unlock()
+			continue
+		}
+		j.stats.Running++
+		s.lock.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.runJob(ctx, j)
+		}()
+	}
+}
+
+func (s *Scheduler) runJob(ctx context.Context, j *job) {
+	start := s.clock.Now()
+	err := saferunJob(ctx, j.run, "modname", s.name, "job", j.name)
+	cost := s.clock.Now().Sub(start)
+
+	if err != nil {
+		slog.Error("fail to run the scheduled job", "modname", s.name, "job", j.name, "err", err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	j.stats.Runs++
+	j.stats.Running--
+	j.stats.LastRun = start
+	j.stats.LastDuration = cost
+	j.stats.LastError = ""
+	if err != nil {
+		j.stats.Failures++
+		j.stats.LastError = err.Error()
+	}
+}
+
+// saferunJob runs the job and recovers its panic as the error,
+// which wraps the panic value if it is an error.
+func saferunJob(ctx context.Context, run func(context.Context) error, logargs ...any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = fmt.Errorf("panic: %w", e)
+			} else {
+				err = fmt.Errorf("panic: %v", r)
+			}
+
+			logargs = append(logargs, "panic", r, "stacks", runtimex.Stacks(2))
+			slog.Error("the scheduled job panics", logargs...)
+		}
+	}()
+	return run(ctx)
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-toolkit/app"
+)
+
+type fakeClock struct {
+	lock    sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance waits until there are n waiters, then moves the clock forward by d.
+func (c *fakeClock) Advance(t *testing.T, n int, d time.Duration) {
+	deadline := time.Now().Add(time.Second * 3)
+	for {
+		c.lock.Lock()
+		if len(c.waiters) >= n {
+			break
+		}
+		c.lock.Unlock()
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout to wait for %d waiters", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+	waiters := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			waiters = append(waiters, w)
+		} else {
+			w.ch <- c.now
+		}
+	}
+	c.waiters = waiters
+}
+
+func runScheduler(t *testing.T, s *Scheduler) (stop func() error) {
+	a := app.New()
+	a.SetSignals()
+	a.SetReloadSignals()
+	a.SetConfigLoader(func(context.Context, *app.App) error { return nil })
+	a.Use(s)
+
+	errch := make(chan error, 1)
+	go func() { errch <- a.Run(context.Background()) }()
+	for !a.Ready() {
+		time.Sleep(time.Millisecond)
+	}
+
+	return sync.OnceValue(func() error { a.Stop(); return <-errch })
+}
+
+func waitJobStats(t *testing.T, s *Scheduler, cond func(JobStats) bool) JobStats {
+	deadline := time.Now().Add(time.Second * 3)
+	for {
+		stats := s.Jobs()[0]
+		if cond(stats) {
+			return stats
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout to wait for the job stats, got %+v", stats)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSchedulerInterval(t *testing.T) {
+	clock := newFakeClock()
+	s := NewScheduler("scheduler")
+	s.SetClock(clock)
+
+	errFail := errors.New("fail")
+	var runs int
+	s.AddInterval("job", time.Minute, func(ctx context.Context) error {
+		if runs++; runs == 2 {
+			return errFail
+		}
+		return nil
+	})
+
+	stop := runScheduler(t, s)
+	defer stop()
+
+	start := clock.Now()
+	clock.Advance(t, 1, time.Minute)
+	stats := waitJobStats(t, s, func(s JobStats) bool { return s.Runs == 1 })
+	if !stats.LastRun.Equal(start.Add(time.Minute)) {
+		t.Errorf("expect last run time %s, but got %s", start.Add(time.Minute), stats.LastRun)
+	}
+	if stats.Schedule != "@every 1m0s" {
+		t.Errorf("expect schedule '%s', but got '%s'", "@every 1m0s", stats.Schedule)
+	}
+
+	clock.Advance(t, 1, time.Minute)
+	stats = waitJobStats(t, s, func(s JobStats) bool { return s.Runs == 2 })
+	if stats.Failures != 1 || stats.LastError != errFail.Error() {
+		t.Errorf("expect 1 failure with the error '%s', but got %+v", errFail, stats)
+	}
+
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSchedulerPanic(t *testing.T) {
+	clock := newFakeClock()
+	s := NewScheduler("scheduler")
+	s.SetClock(clock)
+	if err := s.AddCron("job", "* * * * *", func(ctx context.Context) error { panic("oops") }); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := runScheduler(t, s)
+	defer stop()
+
+	clock.Advance(t, 1, time.Minute)
+	stats := waitJobStats(t, s, func(s JobStats) bool { return s.Runs == 1 })
+	if stats.Failures != 1 || !strings.Contains(stats.LastError, "oops") {
+		t.Errorf("expect the panic is recovered as the error, but got %+v", stats)
+	}
+}
+
+func TestSaferunJobPanicError(t *testing.T) {
+	errPanic := errors.New("panic error")
+	err := saferunJob(context.Background(), func(context.Context) error { panic(errPanic) })
+	if !errors.Is(err, errPanic) {
+		t.Errorf("expect the panic error %v, but got %v", errPanic, err)
+	}
+}
+
+func TestSchedulerSkipIfRunning(t *testing.T) {
+	clock := newFakeClock()
+	s := NewScheduler("scheduler")
+	s.SetClock(clock)
+
+	release := make(chan struct{})
+	var finished bool
+	s.AddInterval("job", time.Minute, func(ctx context.Context) error {
+		<-release
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 10)
+		finished = true
+		return nil
+	}, SkipIfRunning())
+
+	stop := runScheduler(t, s)
+
+	clock.Advance(t, 1, time.Minute)
+	waitJobStats(t, s, func(s JobStats) bool { return s.Running == 1 })
+
+	clock.Advance(t, 1, time.Minute)
+	waitJobStats(t, s, func(s JobStats) bool { return s.Skips == 1 })
+
+	// Stop waits for the running job to finish.
+	close(release)
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !finished {
+		t.Error("expect Stop waits for the running job, but not")
+	}
+	if stats := s.Jobs()[0]; stats.Runs != 1 || stats.Running != 0 {
+		t.Errorf("expect 1 run and no running, but got %+v", stats)
+	}
+}
+
+func TestSchedulerAddJob(t *testing.T) {
+	s := NewScheduler("scheduler")
+	s.AddInterval("job", time.Minute, func(context.Context) error { return nil })
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expect a panic for the duplicate job, but got nil")
+		}
+	}()
+	s.AddInterval("job", time.Minute, func(context.Context) error { return nil })
+}
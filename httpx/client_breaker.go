@@ -0,0 +1,343 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed is the normal state, in which the requests are allowed.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen is the state, in which the requests fail fast.
+	CircuitOpen
+
+	// CircuitHalfOpen is the state after the cool-down period,
+	// in which a few requests are allowed to probe the downstream.
+	CircuitHalfOpen
+)
+
+// String implements the interface fmt.Stringer.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// ErrCircuitOpen is the error wrapped by CircuitOpenError.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError is returned by the circuit breaker client
+// when the circuit breaker of the host is open.
+//
+// It may be wrapped as the http error, such as
+// codeint.ErrServiceUnavailable.Wrap(err).
+type CircuitOpenError struct {
+	Host       string
+	RetryAfter time.Duration // The remaining time of the cool-down period.
+}
+
+// Error implements the interface error.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker is open for host '%s', retry after %s", e.Host, e.RetryAfter)
+}
+
+// Unwrap returns ErrCircuitOpen.
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// CircuitBreakerPolicy is the policy of the circuit breaker.
+type CircuitBreakerPolicy struct {
+	// FailureRatio is the ratio of the failed requests in the window
+	// to open the circuit breaker.
+	//
+	// Default: 0.5
+	FailureRatio float64
+
+	// MinRequests is the minimum number of the requests in the window
+	// before checking the failure ratio.
+	//
+	// Default: 10
+	MinRequests int
+
+	// Window is the duration of the sliding window to count the requests.
+	//
+	// Default: 10s
+	Window time.Duration
+
+	// CoolDown is the duration to keep the circuit breaker open,
+	// then it becomes half-open.
+	//
+	// Default: 30s
+	CoolDown time.Duration
+
+	// HalfOpenRequests is the number of the probe requests allowed in the
+	// half-open state. If all of them succeed, the circuit breaker is closed.
+	// If any fails, it is open again.
+	//
+	// Default: 1
+	HalfOpenRequests int
+
+	// IsFailure reports whether the result of the request is a failure.
+	//
+	// Default: the error is not nil or the status code is 5xx.
+	IsFailure func(rsp *http.Response, err error) bool
+
+	// OnStateChange is called when the state of the circuit breaker
+	// of a host changes, which may be used to log or report the metrics.
+	OnStateChange func(host string, from, to CircuitState)
+
+	now func() time.Time // Only for test
+}
+
+func (p *CircuitBreakerPolicy) setDefaults() {
+	if p.FailureRatio <= 0 || p.FailureRatio > 1 {
+		p.FailureRatio = 0.5
+	}
+	if p.MinRequests <= 0 {
+		p.MinRequests = 10
+	}
+	if p.Window <= 0 {
+		p.Window = time.Second * 10
+	}
+	if p.CoolDown <= 0 {
+		p.CoolDown = time.Second * 30
+	}
+	if p.HalfOpenRequests <= 0 {
+		p.HalfOpenRequests = 1
+	}
+	if p.IsFailure == nil {
+		p.IsFailure = isCircuitFailure
+	}
+	if p.now == nil {
+		p.now = time.Now
+	}
+}
+
+func isCircuitFailure(rsp *http.Response, err error) bool {
+	return err != nil || rsp.StatusCode >= 500
+}
+
+// CircuitBreakerClient wraps the client by WrapClient with the circuit
+// breaker for each host of the request url.
+//
+// While the circuit breaker of the host is open, the request fails fast
+// with *CircuitOpenError without being sent. The requests canceled by
+// the caller are not counted.
+func CircuitBreakerClient(client Client, policy CircuitBreakerPolicy) Client {
+	policy.setDefaults()
+	b := &circuitBreakers{policy: policy, hosts: make(map[string]*circuitBreaker, 8)}
+	return WrapClient(client, b.do)
+}
+
+type circuitBreakers struct {
+	policy CircuitBreakerPolicy
+
+	lock  sync.Mutex
+	hosts map[string]*circuitBreaker
+}
+
+func (bs *circuitBreakers) get(host string) *circuitBreaker {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	b, ok := bs.hosts[host]
+	if !ok {
+		b = newCircuitBreaker(host, &bs.policy)
+		bs.hosts[host] = b
+	}
+	return b
+}
+
+func (bs *circuitBreakers) do(client Client, req *http.Request) (*http.Response, error) {
+	b := bs.get(req.URL.Host)
+	gen, err := b.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := client.Do(req)
+	if errors.Is(err, context.Canceled) && req.Context().Err() != nil {
+		b.done(gen, circuitIgnored)
+	} else if bs.policy.IsFailure(rsp, err) {
+		b.done(gen, circuitFailure)
+	} else {
+		b.done(gen, circuitSuccess)
+	}
+	return rsp, err
+}
+
+const circuitBuckets = 10
+
+type circuitResult int
+
+const (
+	circuitSuccess circuitResult = iota
+	circuitFailure
+	circuitIgnored
+)
+
+type circuitBucket struct {
+	epoch    int64
+	total    int
+	failures int
+}
+
+type circuitBreaker struct {
+	host   string
+	policy *CircuitBreakerPolicy
+
+	lock     sync.Mutex
+	state    CircuitState
+	gen      uint64 // Increase when the state changes.
+	until    time.Time
+	buckets  [circuitBuckets]circuitBucket
+	probes   int // The number of the probe requests in the half-open state.
+	succeeds int // The number of the succeeded probe requests.
+}
+
+func newCircuitBreaker(host string, policy *CircuitBreakerPolicy) *circuitBreaker {
+	return &circuitBreaker{host: host, policy: policy}
+}
+
+// allow reports whether the request is allowed, and returns the generation
+// of the state, which is passed to done with the result.
+func (b *circuitBreaker) allow() (gen uint64, err error) {
+	b.lock.Lock()
+	from, now := b.state, b.policy.now()
+
+	switch {
+	case b.state == CircuitOpen && now.Before(b.until):
+		err = &CircuitOpenError{Host: b.host, RetryAfter: b.until.Sub(now)}
+
+	case b.state == CircuitOpen:
+		b.setState(CircuitHalfOpen, now)
+		fallthrough
+
+	case b.state == CircuitHalfOpen:
+		if b.probes < b.policy.HalfOpenRequests {
+			b.probes++
+		} else {
+			err = &CircuitOpenError{Host: b.host}
+		}
+	}
+
+	gen, to := b.gen, b.state
+	b.lock.Unlock()
+
+	b.notify(from, to)
+	return
+}
+
+// done records the result of the request allowed in the generation gen.
+func (b *circuitBreaker) done(gen uint64, result circuitResult) {
+	b.lock.Lock()
+	from, now := b.state, b.policy.now()
+
+	// Ignore the result of the request sent in the previous state.
+	if gen == b.gen {
+		switch b.state {
+		case CircuitClosed:
+			if result != circuitIgnored && b.record(now, result == circuitFailure) {
+				b.setState(CircuitOpen, now)
+			}
+
+		case CircuitHalfOpen:
+			switch result {
+			case circuitIgnored:
+				b.probes--
+			case circuitFailure:
+				b.setState(CircuitOpen, now)
+			case circuitSuccess:
+				if b.succeeds++; b.succeeds >= b.policy.HalfOpenRequests {
+					b.setState(CircuitClosed, now)
+				}
+			}
+		}
+	}
+
+	to := b.state
+	b.lock.Unlock()
+
+	b.notify(from, to)
+}
+
+// record records the result into the sliding window,
+// and reports whether the circuit breaker should be open.
+func (b *circuitBreaker) record(now time.Time, failure bool) (open bool) {
+	width := int64(b.policy.Window / circuitBuckets)
+	if width <= 0 {
+		width = 1
+	}
+
+	epoch := now.UnixNano() / width
+	bucket := &b.buckets[epoch%circuitBuckets]
+	if bucket.epoch != epoch {
+		*bucket = circuitBucket{epoch: epoch}
+	}
+
+	bucket.total++
+	if failure {
+		bucket.failures++
+	}
+
+	var total, failures int
+	for _, bucket := range b.buckets {
+		if epoch-bucket.epoch < circuitBuckets {
+			total += bucket.total
+			failures += bucket.failures
+		}
+	}
+
+	return total >= b.policy.MinRequests &&
+		float64(failures) >= b.policy.FailureRatio*float64(total)
+}
+
+// setState changes the state, which must be called with the lock.
+func (b *circuitBreaker) setState(state CircuitState, now time.Time) {
+	b.gen++
+	b.state = state
+	b.probes, b.succeeds = 0, 0
+
+	switch state {
+	case CircuitOpen:
+		b.until = now.Add(b.policy.CoolDown)
+	case CircuitClosed:
+		b.buckets = [circuitBuckets]circuitBucket{}
+	}
+}
+
+func (b *circuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.policy.OnStateChange != nil {
+		b.policy.OnStateChange(b.host, from, to)
+	}
+}
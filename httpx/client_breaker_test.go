@@ -0,0 +1,219 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/xgfone/go-toolkit/codeint"
+)
+
+type breakerTest struct {
+	now     time.Time
+	codes   map[string]int
+	calls   int
+	changes []string
+	client  Client
+}
+
+func newBreakerTest(policy CircuitBreakerPolicy) *breakerTest {
+	bt := &breakerTest{now: time.Unix(1700000000, 0), codes: make(map[string]int)}
+	policy.now = func() time.Time { return bt.now }
+	policy.OnStateChange = func(host string, from, to CircuitState) {
+		bt.changes = append(bt.changes, fmt.Sprintf("%s:%s->%s", host, from, to))
+	}
+
+	bt.client = CircuitBreakerClient(DoFunc(func(r *http.Request) (*http.Response, error) {
+		bt.calls++
+		if code := bt.codes[r.URL.Host]; code > 0 {
+			return &http.Response{StatusCode: code, Body: http.NoBody}, nil
+		}
+		return nil, errors.New("connection refused")
+	}), policy)
+	return bt
+}
+
+func (bt *breakerTest) do(host string) error {
+	req, _ := http.NewRequest(http.MethodGet, "http://"+host, nil)
+	_, err := bt.client.Do(req)
+	return err
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	bt := newBreakerTest(CircuitBreakerPolicy{MinRequests: 4, FailureRatio: 0.5, CoolDown: time.Second * 10})
+	bt.codes["a"], bt.codes["b"] = 200, 200
+
+	// 2 failures of 4 requests open the circuit breaker of host a.
+	for i, code := range []int{200, 500, 200, 503} {
+		bt.codes["a"] = code
+		if err := bt.do("a"); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+	}
+
+	calls := bt.calls
+	err := bt.do("a")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expect error ErrCircuitOpen, but got %v", err)
+	}
+	if bt.calls != calls {
+		t.Errorf("expect to fail fast, but the request is sent")
+	}
+
+	var oerr *CircuitOpenError
+	if !errors.As(err, &oerr) {
+		t.Fatalf("expect a *CircuitOpenError, but got %T", err)
+	} else if oerr.Host != "a" || oerr.RetryAfter != time.Second*10 {
+		t.Errorf("unexpected open error: %+v", oerr)
+	}
+
+	if err := codeint.ErrServiceUnavailable.Wrap(err); !errors.Is(err, codeint.ErrServiceUnavailable) ||
+		!errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expect to be wrapped by codeint.ErrServiceUnavailable, but got %v", err)
+	}
+
+	// The circuit breaker is per host.
+	if err := bt.do("b"); err != nil {
+		t.Errorf("unexpected error for the other host: %v", err)
+	}
+
+	// The failed probe request opens it again.
+	bt.now = bt.now.Add(time.Second * 10)
+	bt.codes["a"] = 502
+	if err := bt.do("a"); err != nil {
+		t.Errorf("expect the probe request, but got error %v", err)
+	}
+	if err := bt.do("a"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expect error ErrCircuitOpen, but got %v", err)
+	}
+
+	// The succeeded probe request closes it.
+	bt.now = bt.now.Add(time.Second * 10)
+	bt.codes["a"] = 200
+	for i := range 5 {
+		if err := bt.do("a"); err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+		}
+	}
+
+	expects := []string{
+		"a:closed->open",
+		"a:open->half-open",
+		"a:half-open->open",
+		"a:open->half-open",
+		"a:half-open->closed",
+	}
+	if !slices.Equal(bt.changes, expects) {
+		t.Errorf("expect state changes %v, but got %v", expects, bt.changes)
+	}
+}
+
+func TestCircuitBreakerWindow(t *testing.T) {
+	bt := newBreakerTest(CircuitBreakerPolicy{MinRequests: 2, Window: time.Second * 10})
+
+	// The failures out of the window are not counted.
+	_ = bt.do("a")
+	bt.now = bt.now.Add(time.Second * 11)
+	bt.codes["a"] = 200
+	_ = bt.do("a")
+	_ = bt.do("a")
+	bt.codes["a"] = 0
+	_ = bt.do("a")
+	if len(bt.changes) != 0 {
+		t.Fatalf("expect no state change, but got %v", bt.changes)
+	}
+
+	bt.now = bt.now.Add(time.Second)
+	_ = bt.do("a")
+	if expects := []string{"a:closed->open"}; !slices.Equal(bt.changes, expects) {
+		t.Errorf("expect state changes %v, but got %v", expects, bt.changes)
+	}
+}
+
+func TestCircuitBreakerHalfOpenLimit(t *testing.T) {
+	var policy CircuitBreakerPolicy
+	policy.setDefaults()
+
+	now := time.Unix(1700000000, 0)
+	policy.now = func() time.Time { return now }
+	policy.HalfOpenRequests = 2
+
+	b := newCircuitBreaker("a", &policy)
+	b.setState(CircuitOpen, now)
+	now = now.Add(policy.CoolDown)
+
+	gen1, err1 := b.allow()
+	gen2, err2 := b.allow()
+	_, err3 := b.allow()
+	if err1 != nil || err2 != nil {
+		t.Fatalf("expect 2 probe requests, but got errors %v, %v", err1, err2)
+	}
+	if !errors.Is(err3, ErrCircuitOpen) {
+		t.Fatalf("expect error ErrCircuitOpen for the third probe, but got %v", err3)
+	}
+
+	// The canceled probe request releases the slot.
+	b.done(gen1, circuitIgnored)
+	gen3, err := b.allow()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b.done(gen2, circuitSuccess)
+	if b.state != CircuitHalfOpen {
+		t.Errorf("expect state %s, but got %s", CircuitHalfOpen, b.state)
+	}
+	b.done(gen3, circuitSuccess)
+	if b.state != CircuitClosed {
+		t.Errorf("expect state %s, but got %s", CircuitClosed, b.state)
+	}
+}
+
+func TestCircuitBreakerCanceled(t *testing.T) {
+	client := CircuitBreakerClient(DoFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, r.Context().Err()
+	}), CircuitBreakerPolicy{MinRequests: 1, OnStateChange: func(string, CircuitState, CircuitState) {
+		t.Error("unexpected state change for the canceled request")
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for range 3 {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://a", nil)
+		if _, err := client.Do(req); !errors.Is(err, context.Canceled) {
+			t.Errorf("expect error context.Canceled, but got %v", err)
+		}
+	}
+}
+
+func TestCircuitState(t *testing.T) {
+	for state, s := range map[CircuitState]string{
+		CircuitClosed:   "closed",
+		CircuitOpen:     "open",
+		CircuitHalfOpen: "half-open",
+		CircuitState(9): "CircuitState(9)",
+	} {
+		if state.String() != s {
+			t.Errorf("expect '%s', but got '%s'", s, state.String())
+		}
+	}
+}
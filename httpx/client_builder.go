@@ -0,0 +1,390 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xgfone/go-toolkit/jsonx"
+	"github.com/xgfone/go-toolkit/structx"
+)
+
+// FormFile is a file part of the multipart form body.
+type FormFile struct {
+	FieldName   string
+	FileName    string
+	ContentType string // Default: "application/octet-stream"
+	Content     io.Reader
+}
+
+// RequestBuilder is used to build and send a http request fluently.
+//
+// The error that occurs while building is deferred and returned
+// by Build, Do or the generic function Do.
+//
+// Example:
+//
+//	type User struct {
+//		Id   int64  `json:"id"`
+//		Name string `json:"name"`
+//	}
+//
+//	type Query struct {
+//		Fields []string `query:"field"`
+//		Cache  bool     `query:"cache,omitempty"`
+//	}
+//
+//	user, err := httpx.Do[User](ctx, httpx.NewRequest(http.MethodGet, "http://127.0.0.1/users/{id}").
+//		PathParam("id", "123").
+//		QueryStruct(Query{Fields: []string{"id", "name"}}).
+//		Header(httpx.HeaderAccept, httpx.MIMEApplicationJSON).
+//		Timeout(time.Second * 3))
+type RequestBuilder struct {
+	method  string
+	url     string
+	params  []string
+	query   url.Values
+	header  http.Header
+	body    func() io.Reader
+	ctype   string
+	codes   []int
+	timeout time.Duration
+	client  Client
+	err     error
+}
+
+// NewRequest returns a new request builder with the method and the url,
+// which may contain the path parameters like "{name}", such as
+// "http://127.0.0.1/users/{id}".
+func NewRequest(method, rawurl string) *RequestBuilder {
+	return &RequestBuilder{
+		method: method,
+		url:    rawurl,
+		query:  make(url.Values),
+		header: make(http.Header),
+	}
+}
+
+func (b *RequestBuilder) seterr(err error) *RequestBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// Client sets the client to send the request.
+//
+// Default: GetClient()
+func (b *RequestBuilder) Client(client Client) *RequestBuilder {
+	b.client = client
+	return b
+}
+
+// PathParam sets the value of the path parameter "{name}" in the url,
+// which is escaped by url.PathEscape.
+func (b *RequestBuilder) PathParam(name, value string) *RequestBuilder {
+	b.params = append(b.params, "{"+name+"}", url.PathEscape(value))
+	return b
+}
+
+// Query appends the values of the query parameter.
+func (b *RequestBuilder) Query(key string, values ...string) *RequestBuilder {
+	b.query[key] = append(b.query[key], values...)
+	return b
+}
+
+// QueryStruct appends the query parameters encoded from the struct v
+// with the "query" tag, which is the inverse of BindQuery.
+//
+// See structx.EncodeValues.
+func (b *RequestBuilder) QueryStruct(v any) *RequestBuilder {
+	query, err := structx.EncodeValues(v, bindTagQuery)
+	if err != nil {
+		return b.seterr(fmt.Errorf("fail to encode the query: %w", err))
+	}
+
+	for key, values := range query {
+		b.query[key] = append(b.query[key], values...)
+	}
+	return b
+}
+
+// Header sets the request header, which overrides the value set before.
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	b.header.Set(key, value)
+	return b
+}
+
+// Body sets the request body with the content type.
+//
+// If contentType is empty, the header "Content-Type" is not set.
+func (b *RequestBuilder) Body(contentType string, body io.Reader) *RequestBuilder {
+	b.ctype, b.body = contentType, func() io.Reader { return body }
+	return b
+}
+
+// bytesBody sets the request body with the content type, which can be
+// built repeatedly since each request has its own body reader.
+func (b *RequestBuilder) bytesBody(contentType string, data []byte) *RequestBuilder {
+	b.ctype, b.body = contentType, func() io.Reader { return bytes.NewReader(data) }
+	return b
+}
+
+// JSON sets the request body encoded from v as JSON.
+func (b *RequestBuilder) JSON(v any) *RequestBuilder {
+	buf := bytes.NewBuffer(make([]byte, 0, 256))
+	if err := jsonx.MarshalWriter(buf, v); err != nil {
+		return b.seterr(fmt.Errorf("fail to encode request body: %w", err))
+	}
+	return b.bytesBody(MIMEApplicationJSON, buf.Bytes())
+}
+
+// XML sets the request body encoded from v as XML.
+func (b *RequestBuilder) XML(v any) *RequestBuilder {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return b.seterr(fmt.Errorf("fail to encode request body: %w", err))
+	}
+	return b.bytesBody(MIMEApplicationXMLCharsetUTF8, data)
+}
+
+// Form sets the request body as the url-encoded form.
+func (b *RequestBuilder) Form(form url.Values) *RequestBuilder {
+	return b.bytesBody(MIMEApplicationForm, []byte(form.Encode()))
+}
+
+// Multipart sets the request body as the multipart form
+// with the fields and the files.
+//
+// The contents of the files are read immediately.
+func (b *RequestBuilder) Multipart(fields url.Values, files ...FormFile) *RequestBuilder {
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+	if err := writeMultipart(w, fields, files); err != nil {
+		return b.seterr(fmt.Errorf("fail to encode multipart body: %w", err))
+	}
+	return b.bytesBody(w.FormDataContentType(), buf.Bytes())
+}
+
+func writeMultipart(w *multipart.Writer, fields url.Values, files []FormFile) (err error) {
+	for key, values := range fields {
+		for _, value := range values {
+			if err = w.WriteField(key, value); err != nil {
+				return
+			}
+		}
+	}
+
+	for _, file := range files {
+		ctype := file.ContentType
+		if ctype == "" {
+			ctype = MIMEApplicationOctetStream
+		}
+
+		h := make(textproto.MIMEHeader, 2)
+		h.Set(HeaderContentType, ctype)
+		h.Set(HeaderContentDisposition, fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(file.FieldName), quoteEscaper.Replace(file.FileName)))
+
+		var part io.Writer
+		if part, err = w.CreatePart(h); err != nil {
+			return
+		}
+		if file.Content != nil {
+			if _, err = io.Copy(part, file.Content); err != nil {
+				return
+			}
+		}
+	}
+
+	return w.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// Accept resets the accepted status codes of the response.
+// The response with other status codes is returned as an error.
+//
+// Default: all the 2xx status codes.
+func (b *RequestBuilder) Accept(codes ...int) *RequestBuilder {
+	b.codes = codes
+	return b
+}
+
+// Timeout sets the timeout of the request, which covers
+// the whole process from sending the request to reading the response body.
+//
+// Default: 0, which means no timeout except the context.
+func (b *RequestBuilder) Timeout(timeout time.Duration) *RequestBuilder {
+	b.timeout = timeout
+	return b
+}
+
+// Build builds and returns the http request with the context.
+//
+// Notice: Timeout is not applied, which is only used by Do.
+func (b *RequestBuilder) Build(ctx context.Context) (req *http.Request, err error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	rawurl := b.url
+	if len(b.params) > 0 {
+		rawurl = strings.NewReplacer(b.params...).Replace(rawurl)
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return
+	}
+
+	if len(b.query) > 0 {
+		query := u.Query()
+		for key, values := range b.query {
+			query[key] = append(query[key], values...)
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	var body io.Reader
+	if b.body != nil {
+		body = b.body()
+	}
+
+	req, err = http.NewRequestWithContext(ctx, b.method, u.String(), body)
+	if err != nil {
+		return
+	}
+
+	for key, values := range b.header {
+		req.Header[key] = slices.Clone(values)
+	}
+	if b.body != nil && req.Header.Get(HeaderContentType) == "" {
+		SetContentType(req.Header, b.ctype)
+	}
+
+	return
+}
+
+// Do sends the request and decodes the response body into respbody.
+//
+// The respbody parameter supports the following types:
+//   - nil: response body is ignored, only the status code is checked
+//   - *[]byte or *string: the raw response body
+//   - any other type: response body is decoded by the response Content-Type,
+//     which is XML for "application/xml", "text/xml" and "*+xml",
+//     or JSON for others
+//
+// If the status code is not accepted, the returned error has the methods
+// StatusCode, ResponseBody, Request and Response like DoRequest.
+func (b *RequestBuilder) Do(ctx context.Context, respbody any) (err error) {
+	if b.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+
+	req, err := b.Build(ctx)
+	if err != nil {
+		return
+	}
+
+	client := b.client
+	if client == nil {
+		client = GetClient()
+	}
+
+	rsp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer rsp.Body.Close()
+
+	data, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		err = fmt.Errorf("fail to read the response body: %w", err)
+		return newClientError(req, rsp).WithError(err)
+	}
+
+	logResponse(ctx, req, rsp, data)
+	if !b.isAccepted(rsp.StatusCode) {
+		return newFailedResponseError(req, rsp, data)
+	}
+
+	if err = decodeResponseBody(rsp.Header, data, respbody); err != nil {
+		err = fmt.Errorf("fail to decode the response body: %w", err)
+		return newClientError(req, rsp).WithBody(data).WithError(err)
+	}
+
+	return
+}
+
+func (b *RequestBuilder) isAccepted(code int) bool {
+	if len(b.codes) == 0 {
+		return code >= 200 && code < 300
+	}
+	return slices.Contains(b.codes, code)
+}
+
+func decodeResponseBody(header http.Header, data []byte, respbody any) error {
+	switch v := respbody.(type) {
+	case nil:
+		return nil
+
+	case *[]byte:
+		*v = data
+		return nil
+
+	case *string:
+		*v = string(data)
+		return nil
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	switch ct := ContentType(header); {
+	case ct == MIMEApplicationXML, ct == MIMETextXML, strings.HasSuffix(ct, "+xml"):
+		return xml.Unmarshal(data, respbody)
+
+	default:
+		return jsonx.UnmarshalBytes(data, respbody)
+	}
+}
+
+// Do is a generic convenience function to send the request built by b,
+// and decode the response body into a value of type T.
+//
+// See RequestBuilder.Do.
+func Do[T any](ctx context.Context, b *RequestBuilder) (result T, err error) {
+	if b == nil {
+		return result, errors.New("httpx.Do: request builder is nil")
+	}
+	err = b.Do(ctx, &result)
+	return
+}
@@ -0,0 +1,243 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpx
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestBuilderBuild(t *testing.T) {
+	type Query struct {
+		Page   int      `query:"page"`
+		Fields []string `query:"field"`
+		Cache  bool     `query:"cache,omitempty"`
+	}
+
+	b := NewRequest(http.MethodPost, "http://127.0.0.1/users/{id}/{name}?a=1").
+		PathParam("id", "123").
+		PathParam("name", "a b/c").
+		Query("b", "2").
+		QueryStruct(Query{Page: 1, Fields: []string{"x", "y"}}).
+		Header("X-Key", "value").
+		JSON(map[string]int{"a": 1})
+
+	for range 2 {
+		req, err := b.Build(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if path := req.URL.EscapedPath(); path != "/users/123/a%20b%2Fc" {
+			t.Errorf("expect path '%s', but got '%s'", "/users/123/a%20b%2Fc", path)
+		}
+
+		expect := url.Values{"a": {"1"}, "b": {"2"}, "page": {"1"}, "field": {"x", "y"}}
+		if query := req.URL.Query(); query.Encode() != expect.Encode() {
+			t.Errorf("expect query '%s', but got '%s'", expect.Encode(), query.Encode())
+		}
+
+		if v := req.Header.Get("X-Key"); v != "value" {
+			t.Errorf("expect header '%s', but got '%s'", "value", v)
+		}
+		if ct := ContentType(req.Header); ct != MIMEApplicationJSON {
+			t.Errorf("expect content type '%s', but got '%s'", MIMEApplicationJSON, ct)
+		}
+
+		data, _ := io.ReadAll(req.Body)
+		if body := strings.TrimSpace(string(data)); body != `{"a":1}` {
+			t.Errorf("expect body '%s', but got '%s'", `{"a":1}`, body)
+		}
+	}
+
+	_, err := NewRequest(http.MethodGet, "http://127.0.0.1").QueryStruct(1).Build(context.Background())
+	if err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+}
+
+func TestRequestBuilderBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch ContentType(r.Header) {
+		case MIMEApplicationForm:
+			_ = r.ParseForm()
+			_, _ = io.WriteString(w, r.PostForm.Get("name"))
+
+		case MIMEMultipartForm:
+			if err := r.ParseMultipartForm(1024); err != nil {
+				w.WriteHeader(400)
+				return
+			}
+
+			file, fh, err := r.FormFile("file")
+			if err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			defer file.Close()
+
+			data, _ := io.ReadAll(file)
+			_, _ = io.WriteString(w, r.FormValue("name")+":"+fh.Filename+":"+string(data))
+
+		case MIMEApplicationXML:
+			data, _ := io.ReadAll(r.Body)
+			_, _ = w.Write(data)
+
+		default:
+			w.WriteHeader(415)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	result, err := Do[string](ctx, NewRequest(http.MethodPost, server.URL).Client(server.Client()).
+		Form(url.Values{"name": {"abc"}}))
+	if err != nil {
+		t.Fatal(err)
+	} else if result != "abc" {
+		t.Errorf("expect '%s', but got '%s'", "abc", result)
+	}
+
+	result, err = Do[string](ctx, NewRequest(http.MethodPost, server.URL).Client(server.Client()).
+		Multipart(url.Values{"name": {"abc"}}, FormFile{
+			FieldName: "file",
+			FileName:  "a.txt",
+			Content:   strings.NewReader("content"),
+		}))
+	if err != nil {
+		t.Fatal(err)
+	} else if result != "abc:a.txt:content" {
+		t.Errorf("expect '%s', but got '%s'", "abc:a.txt:content", result)
+	}
+
+	type Item struct {
+		XMLName xml.Name `xml:"item"`
+		Name    string   `xml:"name"`
+	}
+
+	raw, err := Do[[]byte](ctx, NewRequest(http.MethodPost, server.URL).Client(server.Client()).XML(Item{Name: "abc"}))
+	if err != nil {
+		t.Fatal(err)
+	} else if s := string(raw); s != "<item><name>abc</name></item>" {
+		t.Errorf("expect '%s', but got '%s'", "<item><name>abc</name></item>", s)
+	}
+}
+
+func TestRequestBuilderDo(t *testing.T) {
+	type User struct {
+		Id   int64  `json:"id" xml:"id"`
+		Name string `json:"name" xml:"name"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set(HeaderContentType, MIMEApplicationJSONCharsetUTF8)
+			w.WriteHeader(201)
+			_, _ = io.WriteString(w, `{"id":1,"name":"json"}`)
+
+		case "/xml":
+			w.Header().Set(HeaderContentType, MIMEApplicationXMLCharsetUTF8)
+			_, _ = io.WriteString(w, `<User><id>2</id><name>xml</name></User>`)
+
+		case "/empty":
+			w.WriteHeader(204)
+
+		case "/slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+
+		default:
+			w.WriteHeader(404)
+			_, _ = io.WriteString(w, "not found")
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	user, err := Do[User](ctx, NewRequest(http.MethodGet, server.URL+"/json").Client(server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	} else if user != (User{Id: 1, Name: "json"}) {
+		t.Errorf("unexpected user %+v", user)
+	}
+
+	user, err = Do[User](ctx, NewRequest(http.MethodGet, server.URL+"/xml").Client(server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	} else if user != (User{Id: 2, Name: "xml"}) {
+		t.Errorf("unexpected user %+v", user)
+	}
+
+	user, err = Do[User](ctx, NewRequest(http.MethodGet, server.URL+"/empty").Client(server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	} else if user != (User{}) {
+		t.Errorf("unexpected user %+v", user)
+	}
+
+	_, err = Do[User](ctx, NewRequest(http.MethodGet, server.URL+"/json").Client(server.Client()).Accept(200))
+	var ce interface {
+		StatusCode() int
+		ResponseBody() string
+	}
+	if !errors.As(err, &ce) {
+		t.Errorf("expect a client error, but got %v", err)
+	} else if code := ce.StatusCode(); code != 201 {
+		t.Errorf("expect status code %d, but got %d", 201, code)
+	}
+
+	err = NewRequest(http.MethodGet, server.URL+"/missing").Client(server.Client()).Accept(200, 404).Do(ctx, nil)
+	if err != nil {
+		t.Errorf("expect nil, but got %v", err)
+	}
+
+	_, err = Do[User](ctx, NewRequest(http.MethodGet, server.URL+"/missing").Client(server.Client()))
+	if !errors.As(err, &ce) {
+		t.Errorf("expect a client error, but got %v", err)
+	} else if code, body := ce.StatusCode(), ce.ResponseBody(); code != 404 || body != "not found" {
+		t.Errorf("expect 404 and '%s', but got %d and '%s'", "not found", code, body)
+	}
+
+	start := time.Now()
+	err = NewRequest(http.MethodGet, server.URL+"/slow").Client(server.Client()).Timeout(time.Millisecond*50).Do(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect a deadline error, but got %v", err)
+	} else if cost := time.Since(start); cost > time.Millisecond*500 {
+		t.Errorf("expect the timeout to work, but cost %s", cost)
+	}
+
+	var calls []string
+	client := DoFunc(func(r *http.Request) (*http.Response, error) {
+		calls = append(calls, r.URL.Path)
+		return server.Client().Do(r)
+	})
+	if err = NewRequest(http.MethodGet, server.URL+"/json").Client(client).Do(ctx, nil); err != nil {
+		t.Fatal(err)
+	} else if !slices.Equal(calls, []string{"/json"}) {
+		t.Errorf("expect calls %v, but got %v", []string{"/json"}, calls)
+	}
+}
@@ -15,9 +15,15 @@
 package httpx
 
 import (
+	"context"
 	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/xgfone/go-toolkit/codeint"
+	"github.com/xgfone/go-toolkit/result"
 )
 
 func TestClientError(t *testing.T) {
@@ -129,3 +135,86 @@ func TestClientError(t *testing.T) {
 		}
 	})
 }
+
+func TestDecodeResultError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/envelope":
+			result.Failure(w, codeint.ErrNotExist.WithReason("user not found"))
+
+		case "/bare":
+			codeint.ErrUnauthorized.ServeHTTP(w, r)
+
+		case "/text":
+			w.WriteHeader(409)
+			_, _ = io.WriteString(w, `{"Error":{"Code":400004}}`)
+
+		default:
+			w.Header().Set(HeaderContentType, MIMEApplicationJSON)
+			w.WriteHeader(500)
+			_, _ = io.WriteString(w, `{"Error":"internal"}`)
+		}
+	}))
+	defer server.Close()
+
+	SetClient(server.Client())
+	defer SetClient(http.DefaultClient)
+
+	ctx := context.Background()
+	newRequest := func(path string) *http.Request {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		return req
+	}
+
+	err := DoRequest(ctx, newRequest("/envelope"), nil)
+	if errors.Is(err, codeint.ErrNotExist) {
+		t.Errorf("expect the error decoder to be disabled by default")
+	}
+
+	SetClientErrorDecoder(DecodeResultError)
+	defer SetClientErrorDecoder(nil)
+	if GetClientErrorDecoder() == nil {
+		t.Errorf("expect the error decoder, but got nil")
+	}
+
+	err = DoRequest(ctx, newRequest("/envelope"), nil)
+	if !errors.Is(err, codeint.ErrNotExist) {
+		t.Errorf("expect codeint.ErrNotExist, but got %v", err)
+	}
+
+	var ce codeint.Error
+	if !errors.As(err, &ce) {
+		t.Errorf("expect a codeint.Error, but got %v", err)
+	} else if ce.Reason != "user not found" || ce.Status != 409 {
+		t.Errorf("expect reason '%s' and status %d, but got '%s' and %d",
+			"user not found", 409, ce.Reason, ce.Status)
+	}
+
+	var cerr interface {
+		StatusCode() int
+		ResponseBody() string
+		Request() *http.Request
+		Response() *http.Response
+	}
+	if !errors.As(err, &cerr) {
+		t.Errorf("expect a client error, but got %v", err)
+	} else if code := cerr.StatusCode(); code != 409 {
+		t.Errorf("expect status code %d, but got %d", 409, code)
+	} else if cerr.Request() == nil || cerr.Response() == nil || cerr.ResponseBody() == "" {
+		t.Errorf("expect the request, response and body, but got nothing")
+	}
+
+	err = NewRequest(http.MethodGet, server.URL+"/bare").Client(server.Client()).Do(ctx, nil)
+	if !errors.Is(err, codeint.ErrUnauthorized) {
+		t.Errorf("expect codeint.ErrUnauthorized, but got %v", err)
+	}
+
+	for _, path := range []string{"/text", "/string"} {
+		err = DoRequest(ctx, newRequest(path), nil)
+		if !errors.As(err, &cerr) {
+			t.Errorf("%s: expect a client error, but got %v", path, err)
+		} else if errors.As(err, &ce) {
+			t.Errorf("%s: unexpected codeint.Error %v", path, ce)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpx
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	_ ClientMiddleware = ClientMiddlewares(nil)
+	_ ClientMiddleware = ClientMiddlewareFunc(nil)
+)
+
+// ClientMiddleware is a HTTP client middleware.
+type ClientMiddleware interface {
+	HTTPClient(next Client) Client
+}
+
+// ClientMiddlewareFunc is the client middleware function.
+type ClientMiddlewareFunc func(next Client) Client
+
+// HTTPClient implements the interface ClientMiddleware.
+func (f ClientMiddlewareFunc) HTTPClient(next Client) Client { return f(next) }
+
+// ClientMiddlewares is a set of client middlewares.
+//
+// Example:
+//
+//	mws := httpx.ClientMiddlewares{
+//		httpx.ClientRequestID(),
+//		httpx.ClientUserAgent("myapp/1.0"),
+//		httpx.ClientLogger(slog.LevelDebug),
+//	}
+//	httpx.SetClient(mws.HTTPClient(http.DefaultClient))
+type ClientMiddlewares []ClientMiddleware
+
+// HTTPClient implements the ClientMiddleware interface to returns a new client,
+// in which the first middleware is the outermost.
+func (ms ClientMiddlewares) HTTPClient(next Client) Client {
+	for i := len(ms) - 1; i >= 0; i-- {
+		next = ms[i].HTTPClient(next)
+	}
+	return next
+}
+
+// Sort tries to sort the client middlewares by priority from big to small.
+//
+// If the middleware implements the interface{ Priority() int }, use it.
+// Otherwise, use 1 instead.
+func (ms ClientMiddlewares) Sort() {
+	slices.SortStableFunc(ms, func(a, b ClientMiddleware) int {
+		return getPriority(b) - getPriority(a) // From bigger to smaller
+	})
+}
+
+// PriorityClientMiddleware returns a client middleware with the given priority.
+func PriorityClientMiddleware(priority int, m ClientMiddleware) ClientMiddleware {
+	return _PriorityClientMiddleware{ClientMiddleware: m, priority: priority}
+}
+
+// PriorityClientMiddlewareFunc returns a client middleware with the given priority.
+func PriorityClientMiddlewareFunc(priority int, m ClientMiddlewareFunc) ClientMiddleware {
+	return PriorityClientMiddleware(priority, m)
+}
+
+type _PriorityClientMiddleware struct {
+	ClientMiddleware
+	priority int
+}
+
+func (m _PriorityClientMiddleware) Priority() int {
+	return m.priority
+}
+
+// UnwrapInnerClient unwraps the client layer by layer by UnwrapClient,
+// and returns the innermost client.
+//
+// Return client itself if it has not implemented the interface { Unwrap() Client }.
+func UnwrapInnerClient(client Client) Client {
+	for {
+		inner := UnwrapClient(client)
+		if inner == nil {
+			return client
+		}
+		client = inner
+	}
+}
+
+// wrapClientMiddleware returns a client middleware to wrap the client by WrapClient.
+func wrapClientMiddleware(f func(Client, *http.Request) (*http.Response, error)) ClientMiddleware {
+	return ClientMiddlewareFunc(func(next Client) Client { return WrapClient(next, f) })
+}
+
+// setDefaultHeaders returns the request with the headers which are not set,
+// which clones the request before modifying it.
+func setDefaultHeaders(req *http.Request, headers http.Header) *http.Request {
+	cloned := false
+	for key, values := range headers {
+		if len(values) == 0 || len(req.Header.Values(key)) > 0 {
+			continue
+		}
+
+		if !cloned {
+			req = req.Clone(req.Context())
+			cloned = true
+		}
+		req.Header[http.CanonicalHeaderKey(key)] = slices.Clone(values)
+	}
+	return req
+}
+
+// ClientHeaders returns a client middleware to set the default headers
+// of the requests, which are only set if not set by the request.
+func ClientHeaders(headers http.Header) ClientMiddleware {
+	headers = headers.Clone()
+	return wrapClientMiddleware(func(c Client, r *http.Request) (*http.Response, error) {
+		return c.Do(setDefaultHeaders(r, headers))
+	})
+}
+
+// ClientUserAgent returns a client middleware to set the default header
+// User-Agent of the requests.
+func ClientUserAgent(ua string) ClientMiddleware {
+	return ClientHeaders(http.Header{HeaderUserAgent: []string{ua}})
+}
+
+// ClientRequestID returns a client middleware to propagate the request id,
+// that's, the header X-Request-Id of the incoming request in httpx.Context,
+// which is got from the context of the outgoing request.
+func ClientRequestID() ClientMiddleware {
+	return wrapClientMiddleware(func(c Client, r *http.Request) (*http.Response, error) {
+		if ctx := GetContext(r.Context()); ctx != nil && ctx.Request != nil {
+			if id := ctx.Request.Header.Get(HeaderXRequestID); id != "" {
+				r = setDefaultHeaders(r, http.Header{HeaderXRequestID: []string{id}})
+			}
+		}
+		return c.Do(r)
+	})
+}
+
+// ClientTiming returns a client middleware to measure the duration of each
+// request until the response header is received, and report it by observe.
+func ClientTiming(observe func(req *http.Request, rsp *http.Response, err error, cost time.Duration)) ClientMiddleware {
+	if observe == nil {
+		panic("httpx.ClientTiming: observe function must not be nil")
+	}
+
+	return wrapClientMiddleware(func(c Client, r *http.Request) (*http.Response, error) {
+		start := time.Now()
+		rsp, err := c.Do(r)
+		observe(r, rsp, err, time.Since(start))
+		return rsp, err
+	})
+}
+
+// DefaultRedactedHeaders is the default sensitive headers redacted by ClientLogger.
+var DefaultRedactedHeaders = []string{
+	HeaderAuthorization,
+	HeaderProxyAuthorization,
+	HeaderCookie,
+	HeaderSetCookie,
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+// DefaultRedactedQueries is the default sensitive query parameters
+// redacted by ClientLogger.
+var DefaultRedactedQueries = []string{
+	"access_token",
+	"api_key",
+	"password",
+	"secret",
+	"signature",
+	"token",
+}
+
+const redactedValue = "REDACTED"
+
+// ClientLogger returns a client middleware to log the requests at the level
+// by the default slog logger.
+//
+// The values of the sensitive headers and query parameters, including
+// DefaultRedactedHeaders, DefaultRedactedQueries and redacts, which are
+// case-insensitive, and the password in the url are redacted.
+func ClientLogger(level slog.Level, redacts ...string) ClientMiddleware {
+	headers := make(map[string]struct{}, len(DefaultRedactedHeaders)+len(redacts))
+	queries := make(map[string]struct{}, len(DefaultRedactedQueries)+len(redacts))
+	for _, name := range DefaultRedactedHeaders {
+		headers[strings.ToLower(name)] = struct{}{}
+	}
+	for _, name := range DefaultRedactedQueries {
+		queries[strings.ToLower(name)] = struct{}{}
+	}
+	for _, name := range redacts {
+		headers[strings.ToLower(name)] = struct{}{}
+		queries[strings.ToLower(name)] = struct{}{}
+	}
+
+	return wrapClientMiddleware(func(c Client, r *http.Request) (*http.Response, error) {
+		ctx := r.Context()
+		if !slog.Default().Enabled(ctx, level) {
+			return c.Do(r)
+		}
+
+		start := time.Now()
+		rsp, err := c.Do(r)
+		cost := time.Since(start)
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("url", redactURL(r.URL, queries)),
+			slog.Any("reqheader", redactHeader(r.Header, headers)),
+			slog.String("cost", cost.String()),
+		}
+
+		if err != nil {
+			attrs = append(attrs, slog.String("err", err.Error()))
+		} else {
+			attrs = append(attrs,
+				slog.Int("statuscode", rsp.StatusCode),
+				slog.Any("respheader", redactHeader(rsp.Header, headers)),
+			)
+		}
+
+		slog.LogAttrs(ctx, level, "log http client request", attrs...)
+		return rsp, err
+	})
+}
+
+func redactHeader(header http.Header, redacts map[string]struct{}) http.Header {
+	redacted := make(http.Header, len(header))
+	for key, values := range header {
+		if _, ok := redacts[strings.ToLower(key)]; ok {
+			values = []string{redactedValue}
+		}
+		redacted[key] = values
+	}
+	return redacted
+}
+
+func redactURL(u *url.URL, redacts map[string]struct{}) string {
+	if u == nil {
+		return ""
+	}
+
+	_u := *u
+	if _u.User != nil {
+		if _, ok := _u.User.Password(); ok {
+			_u.User = url.UserPassword(_u.User.Username(), redactedValue)
+		}
+	}
+
+	if _u.RawQuery != "" {
+		query := _u.Query()
+		for key := range query {
+			if _, ok := redacts[strings.ToLower(key)]; ok {
+				query[key] = []string{redactedValue}
+			}
+		}
+		_u.RawQuery = query.Encode()
+	}
+
+	return _u.String()
+}
@@ -22,6 +22,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/xgfone/go-toolkit/codeint"
 	"github.com/xgfone/go-toolkit/internal/pools"
 	"github.com/xgfone/go-toolkit/jsonx"
 	"github.com/xgfone/go-toolkit/unsafex"
@@ -40,6 +41,18 @@ func newClientError(req *http.Request, rsp *http.Response) _ClientError {
 	return _ClientError{req: req, rsp: rsp, code: rsp.StatusCode}
 }
 
+// newFailedResponseError returns the error of the response
+// whose status code is not accepted.
+func newFailedResponseError(req *http.Request, rsp *http.Response, body []byte) _ClientError {
+	err := newClientError(req, rsp).WithBody(body)
+	if decode := errorDecoder; decode != nil {
+		if e := decode(rsp, body); e != nil {
+			err = err.WithError(e)
+		}
+	}
+	return err
+}
+
 func (e _ClientError) WithError(err error) _ClientError {
 	e.err = err
 	return e
@@ -72,6 +85,58 @@ func (e _ClientError) Error() string {
 	}
 }
 
+var errorDecoder func(rsp *http.Response, body []byte) error
+
+// SetClientErrorDecoder resets the decoder to decode the error
+// from the body of the failed response, which is used by DoRequest
+// and RequestBuilder.Do. If decode is nil, it is disabled.
+//
+// If the decoder returns a non-nil error, it is wrapped by the returned
+// client error, which still has the methods StatusCode, ResponseBody,
+// Request and Response. So, for example, DecodeResultError makes
+// errors.Is(err, codeint.ErrNotExist) work across the services.
+//
+// Default: nil
+func SetClientErrorDecoder(decode func(rsp *http.Response, body []byte) error) {
+	errorDecoder = decode
+}
+
+// GetClientErrorDecoder returns the decoder to decode the error
+// from the body of the failed response.
+func GetClientErrorDecoder() func(rsp *http.Response, body []byte) error {
+	return errorDecoder
+}
+
+// DecodeResultError decodes the error from the JSON response body,
+// which is either the envelope result.Response with the error of
+// codeint.Error, or the bare codeint.Error.
+//
+// The status of the returned codeint.Error is set to the status code
+// of the response. It returns nil if the response body is not such one.
+func DecodeResultError(rsp *http.Response, body []byte) error {
+	if len(body) == 0 || ContentType(rsp.Header) != MIMEApplicationJSON {
+		return nil
+	}
+
+	var response struct {
+		Error *codeint.Error
+	}
+	if jsonx.UnmarshalBytes(body, &response) != nil {
+		return nil
+	}
+
+	if response.Error == nil {
+		var err codeint.Error
+		if jsonx.UnmarshalBytes(body, &err) != nil || err.Code == 0 {
+			return nil
+		}
+		response.Error = &err
+	}
+
+	response.Error.Status = rsp.StatusCode
+	return *response.Error
+}
+
 // Get sends a GET request to the specified URL and decodes the response body
 // into the provided response object.
 //
@@ -155,7 +220,8 @@ func Request(ctx context.Context, method, url string, respbody, reqbody any) (er
 // It will log the request and response details at the debug level if the debug log is enabled.
 //
 // Returns an error if the request fails, the response status code is not 200,
-// or the response body decoding fails.
+// or the response body decoding fails. For the status code that is not 200,
+// the error decoded by the decoder set by SetClientErrorDecoder is wrapped.
 func DoRequest(ctx context.Context, req *http.Request, respbody any) (err error) {
 	rsp, err := GetClient().Do(req)
 	if err != nil {
@@ -173,19 +239,9 @@ func DoRequest(ctx context.Context, req *http.Request, respbody any) (err error)
 		return newClientError(req, rsp).WithError(err)
 	}
 
-	if slog.Default().Enabled(ctx, slog.LevelDebug) {
-		slog.Debug("log http response",
-			"method", req.Method,
-			"url", req.URL.String(),
-			"reqheader", req.Header,
-			"reqbody", readRequestBody(req),
-			"statuscode", rsp.StatusCode,
-			"respheader", rsp.Header,
-			"respbody", _JSONBody(unsafex.String(data)))
-	}
-
+	logResponse(ctx, req, rsp, data)
 	if rsp.StatusCode != 200 {
-		return newClientError(req, rsp).WithBody(data)
+		return newFailedResponseError(req, rsp, data)
 	}
 
 	if respbody != nil && len(data) > 0 {
@@ -198,6 +254,19 @@ func DoRequest(ctx context.Context, req *http.Request, respbody any) (err error)
 	return
 }
 
+func logResponse(ctx context.Context, req *http.Request, rsp *http.Response, data []byte) {
+	if slog.Default().Enabled(ctx, slog.LevelDebug) {
+		slog.Debug("log http response",
+			"method", req.Method,
+			"url", req.URL.String(),
+			"reqheader", req.Header,
+			"reqbody", readRequestBody(req),
+			"statuscode", rsp.StatusCode,
+			"respheader", rsp.Header,
+			"respbody", _JSONBody(unsafex.String(data)))
+	}
+}
+
 func readRequestBody(req *http.Request) any {
 	if req.Body == nil {
 		return nil
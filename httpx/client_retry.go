@@ -0,0 +1,315 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/xgfone/go-toolkit/netx"
+)
+
+// DefaultRetryStatusCodes is the default status codes to retry the request.
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy is the policy to retry the http request.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of the attempts including the first.
+	//
+	// Default: 3
+	MaxAttempts int
+
+	// MinBackoff and MaxBackoff are the bounds of the exponential backoff,
+	// which starts from MinBackoff and doubles for each retry. A random
+	// jitter is applied, so the actual delay is between half and all of it.
+	//
+	// Default: 100ms, 10s
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// MaxRetryAfter is the maximum delay accepted from the header Retry-After.
+	// If the server requires a longer delay, the response is returned
+	// without retry.
+	//
+	// Default: 1m
+	MaxRetryAfter time.Duration
+
+	// StatusCodes is the status codes of the response to retry the request.
+	//
+	// Default: DefaultRetryStatusCodes
+	StatusCodes []int
+
+	// RetryNonIdempotent allows to retry the non-idempotent requests,
+	// such as POST and PATCH without the header Idempotency-Key.
+	//
+	// Default: false
+	RetryNonIdempotent bool
+}
+
+func (p *RetryPolicy) setDefaults() {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.MinBackoff <= 0 {
+		p.MinBackoff = time.Millisecond * 100
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = time.Second * 10
+	}
+	if p.MaxBackoff < p.MinBackoff {
+		p.MaxBackoff = p.MinBackoff
+	}
+	if p.MaxRetryAfter <= 0 {
+		p.MaxRetryAfter = time.Minute
+	}
+	if p.StatusCodes == nil {
+		p.StatusCodes = DefaultRetryStatusCodes
+	}
+}
+
+// RetryClient wraps the client by WrapClient to retry the request
+// by the policy.
+//
+// The request is retried on the connection error, the timeout error
+// reported by netx.IsTimeout, or the response with the status code in
+// policy.StatusCodes, and only for the idempotent requests, that's,
+// the methods GET, HEAD, OPTIONS, TRACE, PUT and DELETE, or the request
+// having the header Idempotency-Key or X-Idempotency-Key,
+// unless policy.RetryNonIdempotent is true.
+//
+// The delay between the attempts honors the header Retry-After
+// of the response, or uses the exponential backoff with jitter.
+// When the context of the request is done, it stops retrying immediately
+// and returns the context error.
+//
+// The request body is rewound by req.GetBody, or by seeking if it implements
+// io.Seeker. Or, the request with the body is never retried.
+//
+// Example:
+//
+//	httpx.SetClient(httpx.RetryClient(http.DefaultClient, httpx.RetryPolicy{}))
+func RetryClient(client Client, policy RetryPolicy) Client {
+	policy.setDefaults()
+	return WrapClient(client, policy.do)
+}
+
+func (p RetryPolicy) do(client Client, req *http.Request) (rsp *http.Response, err error) {
+	if !p.RetryNonIdempotent && !isIdempotent(req) {
+		return client.Do(req)
+	}
+
+	rewinder, ok := newBodyRewinder(req)
+	if !ok {
+		return client.Do(req)
+	}
+	defer rewinder.Close()
+
+	var r *http.Request
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		if r, err = rewinder.Request(attempt); err != nil {
+			return nil, err
+		}
+
+		rsp, err = client.Do(r)
+		if attempt >= p.MaxAttempts || !p.shouldRetry(ctx, rsp, err) {
+			return
+		}
+
+		delay, ok := p.backoff(attempt, rsp)
+		if !ok {
+			return
+		}
+
+		if rsp != nil {
+			drainBody(rsp.Body)
+			rsp = nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (p RetryPolicy) shouldRetry(ctx context.Context, rsp *http.Response, err error) bool {
+	switch {
+	case ctx.Err() != nil:
+		return false
+	case err != nil:
+		return isRetryableError(err)
+	default:
+		return slices.Contains(p.StatusCodes, rsp.StatusCode)
+	}
+}
+
+// backoff returns the delay before the next attempt,
+// and false if the delay required by the server is too long.
+func (p RetryPolicy) backoff(attempt int, rsp *http.Response) (time.Duration, bool) {
+	if rsp != nil {
+		if delay, ok := parseRetryAfter(rsp.Header.Get(HeaderRetryAfter)); ok {
+			return delay, delay <= p.MaxRetryAfter
+		}
+	}
+
+	delay := p.MaxBackoff
+	if shift := attempt - 1; shift < 32 {
+		if d := p.MinBackoff << shift; d > 0 && d < delay {
+			delay = d
+		}
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1), true
+}
+
+// parseRetryAfter parses the header Retry-After,
+// which is either the delay seconds or the http date.
+func parseRetryAfter(value string) (delay time.Duration, ok bool) {
+	if value == "" {
+		return
+	}
+
+	if secs, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+
+	return
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	_, ok1 := req.Header["Idempotency-Key"]
+	_, ok2 := req.Header["X-Idempotency-Key"]
+	return ok1 || ok2
+}
+
+func isRetryableError(err error) bool {
+	if netx.IsTimeout(err) {
+		return true
+	}
+
+	// Such as "no such host", which fails again.
+	var dnserr *net.DNSError
+	if errors.As(err, &dnserr) {
+		return dnserr.IsTemporary
+	}
+
+	// The request has not been sent if failing to dial.
+	var operr *net.OpError
+	if errors.As(err, &operr) && operr.Op == "dial" {
+		return true
+	}
+
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		isConnRefusedOrReset(err)
+}
+
+// bodyRewinder rewinds the request body for each attempt.
+type bodyRewinder struct {
+	req    *http.Request
+	seeker io.Seeker
+	offset int64
+}
+
+// newBodyRewinder returns a new body rewinder,
+// and false if the request body cannot be rewound.
+func newBodyRewinder(req *http.Request) (*bodyRewinder, bool) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return &bodyRewinder{req: req}, true
+	}
+
+	seeker, ok := req.Body.(io.Seeker)
+	if !ok {
+		return nil, false
+	}
+
+	offset, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, false
+	}
+
+	return &bodyRewinder{req: req, seeker: seeker, offset: offset}, true
+}
+
+// Request returns the request for the attempt starting from 1.
+func (b *bodyRewinder) Request(attempt int) (*http.Request, error) {
+	switch {
+	case b.seeker != nil:
+		if _, err := b.seeker.Seek(b.offset, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("fail to rewind the request body: %w", err)
+		}
+
+		// The transport closes the request body after sending it,
+		// so the original body is closed only after all the attempts.
+		r := b.req.Clone(b.req.Context())
+		r.Body = io.NopCloser(b.req.Body)
+		return r, nil
+
+	case attempt == 1, b.req.GetBody == nil:
+		return b.req, nil
+
+	default:
+		body, err := b.req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("fail to rewind the request body: %w", err)
+		}
+
+		r := b.req.Clone(b.req.Context())
+		r.Body = body
+		return r, nil
+	}
+}
+
+// Close closes the original request body if it is not closed by the transport.
+func (b *bodyRewinder) Close() {
+	if b.seeker != nil {
+		_ = b.req.Body.Close()
+	}
+}
+
+// drainBody reads a little of the body and closes it,
+// so that the connection may be reused.
+func drainBody(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, 4096)
+	_ = body.Close()
+}
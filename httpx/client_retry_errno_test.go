@@ -0,0 +1,42 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !plan9
+
+package httpx
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestIsRetryableErrno(t *testing.T) {
+	for _, tt := range []struct {
+		err    error
+		expect bool
+	}{
+		{err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, expect: true},
+		{err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, expect: true},
+		{err: &net.OpError{Op: "write", Net: "tcp", Err: syscall.EPIPE}, expect: false},
+		{err: &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}, expect: false},
+		{err: &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}}, expect: true},
+		{err: errors.New("unsupported protocol scheme"), expect: false},
+	} {
+		if retryable := isRetryableError(tt.err); retryable != tt.expect {
+			t.Errorf("%v: expect %v, but got %v", tt.err, tt.expect, retryable)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// retryServer returns a fake client which responds the status codes in turn,
+// and records the request bodies.
+func retryServer(codes ...int) (client Client, bodies *[]string) {
+	bodies = new([]string)
+	client = DoFunc(func(r *http.Request) (*http.Response, error) {
+		var body string
+		if r.Body != nil {
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				return nil, err
+			}
+			body = string(data)
+			_ = r.Body.Close()
+		}
+
+		code := codes[min(len(*bodies), len(codes)-1)]
+		*bodies = append(*bodies, body)
+		return &http.Response{
+			StatusCode: code,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(http.StatusText(code))),
+			Request:    r,
+		}, nil
+	})
+	return
+}
+
+var testRetryPolicy = RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond * 5}
+
+type seekBody struct {
+	*bytes.Reader
+	closed int
+}
+
+func (b *seekBody) Close() error { b.closed++; return nil }
+
+func TestRetryClientStatusCode(t *testing.T) {
+	server, bodies := retryServer(503, 502, 200)
+	client := RetryClient(server, testRetryPolicy)
+
+	req, _ := http.NewRequest(http.MethodPut, "http://127.0.0.1", strings.NewReader("data"))
+	rsp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+
+	if rsp.StatusCode != 200 {
+		t.Errorf("expect status code %d, but got %d", 200, rsp.StatusCode)
+	}
+	if len(*bodies) != 3 {
+		t.Fatalf("expect %d attempts, but got %d", 3, len(*bodies))
+	}
+	for i, body := range *bodies {
+		if body != "data" {
+			t.Errorf("%d: expect request body '%s', but got '%s'", i, "data", body)
+		}
+	}
+
+	// Return the last response when exceeding the maximum attempts.
+	server, bodies = retryServer(504)
+	rsp, err = RetryClient(server, testRetryPolicy).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+
+	if rsp.StatusCode != 504 {
+		t.Errorf("expect status code %d, but got %d", 504, rsp.StatusCode)
+	}
+	if len(*bodies) != 3 {
+		t.Errorf("expect %d attempts, but got %d", 3, len(*bodies))
+	}
+}
+
+func TestRetryClientSeeker(t *testing.T) {
+	server, bodies := retryServer(503, 200)
+	client := RetryClient(server, testRetryPolicy)
+
+	body := &seekBody{Reader: bytes.NewReader([]byte("skipdata"))}
+	_, _ = body.Seek(4, io.SeekStart)
+
+	req, _ := http.NewRequest(http.MethodPut, "http://127.0.0.1", body)
+	if req.GetBody != nil {
+		t.Fatal("expect no GetBody for the custom body")
+	}
+
+	rsp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+
+	if len(*bodies) != 2 || (*bodies)[0] != "data" || (*bodies)[1] != "data" {
+		t.Errorf("expect the rewound request bodies, but got %q", *bodies)
+	}
+	if body.closed != 1 {
+		t.Errorf("expect the request body to be closed once, but got %d", body.closed)
+	}
+}
+
+func TestRetryClientNotRetry(t *testing.T) {
+	newRequest := func(method string, body io.Reader) *http.Request {
+		req, _ := http.NewRequest(method, "http://127.0.0.1", body)
+		return req
+	}
+
+	nonRewindable := newRequest(http.MethodPut, strings.NewReader("data"))
+	nonRewindable.GetBody = nil
+	nonRewindable.Body = io.NopCloser(nonRewindable.Body)
+
+	idempotent := newRequest(http.MethodPost, nil)
+	idempotent.Header.Set("Idempotency-Key", "abc")
+
+	tests := []struct {
+		name     string
+		req      *http.Request
+		policy   RetryPolicy
+		attempts int
+	}{
+		{name: "post", req: newRequest(http.MethodPost, nil), attempts: 1},
+		{name: "patch", req: newRequest(http.MethodPatch, nil), attempts: 1},
+		{name: "post-idempotency-key", req: idempotent, attempts: 3},
+		{name: "post-opt-in", req: newRequest(http.MethodPost, nil), attempts: 3,
+			policy: RetryPolicy{RetryNonIdempotent: true}},
+		{name: "non-rewindable", req: nonRewindable, attempts: 1},
+		{name: "custom-status-codes", req: newRequest(http.MethodGet, nil), attempts: 1,
+			policy: RetryPolicy{StatusCodes: []int{500}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := tt.policy
+			policy.MinBackoff, policy.MaxBackoff = testRetryPolicy.MinBackoff, testRetryPolicy.MaxBackoff
+
+			server, bodies := retryServer(503)
+			rsp, err := RetryClient(server, policy).Do(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsp.Body.Close()
+
+			if len(*bodies) != tt.attempts {
+				t.Errorf("expect %d attempts, but got %d", tt.attempts, len(*bodies))
+			}
+		})
+	}
+}
+
+func TestRetryClientError(t *testing.T) {
+	for _, tt := range []struct {
+		err      error
+		attempts int
+	}{
+		{err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, attempts: 3},
+		{err: &net.OpError{Op: "write", Net: "tcp", Err: errors.New("broken pipe")}, attempts: 1},
+		{err: io.ErrUnexpectedEOF, attempts: 3},
+		{err: context.DeadlineExceeded, attempts: 3},
+		{err: errors.New("unsupported protocol scheme"), attempts: 1},
+	} {
+		var attempts int
+		client := RetryClient(DoFunc(func(r *http.Request) (*http.Response, error) {
+			attempts++
+			return nil, tt.err
+		}), testRetryPolicy)
+
+		req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
+		if _, err := client.Do(req); !errors.Is(err, tt.err) {
+			t.Errorf("%v: expect the error, but got %v", tt.err, err)
+		}
+		if attempts != tt.attempts {
+			t.Errorf("%v: expect %d attempts, but got %d", tt.err, tt.attempts, attempts)
+		}
+	}
+}
+
+func TestRetryClientRetryAfter(t *testing.T) {
+	var attempts int
+	server := DoFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		rsp := &http.Response{StatusCode: 429, Header: http.Header{}, Body: http.NoBody}
+		rsp.Header.Set(HeaderRetryAfter, "3600")
+		return rsp, nil
+	})
+
+	// The server requires too long delay, so return the response directly.
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
+	rsp, err := RetryClient(server, testRetryPolicy).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	} else if rsp.StatusCode != 429 {
+		t.Errorf("expect status code %d, but got %d", 429, rsp.StatusCode)
+	}
+	if attempts != 1 {
+		t.Errorf("expect %d attempt, but got %d", 1, attempts)
+	}
+
+	for _, tt := range []struct {
+		value string
+		delay time.Duration
+		ok    bool
+	}{
+		{value: ""},
+		{value: "abc"},
+		{value: "-1"},
+		{value: "0", ok: true},
+		{value: "120", delay: time.Minute * 2, ok: true},
+		{value: "Mon, 02 Jan 2006 15:04:05 GMT", ok: true},
+	} {
+		delay, ok := parseRetryAfter(tt.value)
+		if delay != tt.delay || ok != tt.ok {
+			t.Errorf("%q: expect (%s, %v), but got (%s, %v)", tt.value, tt.delay, tt.ok, delay, ok)
+		}
+	}
+
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if delay, ok := parseRetryAfter(future); !ok || delay < time.Minute*59 || delay > time.Hour {
+		t.Errorf("unexpected delay %s for the http date", delay)
+	}
+}
+
+func TestRetryClientBackoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: time.Second, MaxBackoff: time.Second * 5}
+	policy.setDefaults()
+
+	for attempt, max := range []time.Duration{time.Second, time.Second * 2, time.Second * 4, time.Second * 5, time.Second * 5} {
+		for range 100 {
+			if delay, _ := policy.backoff(attempt+1, nil); delay < max/2 || delay > max {
+				t.Fatalf("attempt %d: expect delay in [%s, %s], but got %s", attempt+1, max/2, max, delay)
+			}
+		}
+	}
+
+	if delay, _ := policy.backoff(100, nil); delay > policy.MaxBackoff {
+		t.Errorf("expect delay less than %s, but got %s", policy.MaxBackoff, delay)
+	}
+}
+
+func TestRetryClientContextCancel(t *testing.T) {
+	server, bodies := retryServer(503)
+	client := RetryClient(server, RetryPolicy{MinBackoff: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*50, cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://127.0.0.1", nil)
+	start := time.Now()
+	if _, err := client.Do(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("expect error context.Canceled, but got %v", err)
+	}
+	if cost := time.Since(start); cost > time.Second*5 {
+		t.Errorf("expect to stop retrying immediately, but took %s", cost)
+	}
+	if len(*bodies) != 1 {
+		t.Errorf("expect %d attempt, but got %d", 1, len(*bodies))
+	}
+}
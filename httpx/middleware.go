@@ -75,7 +75,7 @@ func (m _PriorityMiddleware) Priority() int {
 	return m.priority
 }
 
-func getPriority(m Middleware) int {
+func getPriority(m any) int {
 	if p, ok := m.(_Priority); ok {
 		return p.Priority()
 	}
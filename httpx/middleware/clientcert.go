@@ -0,0 +1,69 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"crypto/x509"
+	"net/http"
+
+	"github.com/xgfone/go-toolkit/httpx"
+)
+
+// ClientIdentity is the identity of the verified TLS client certificate.
+type ClientIdentity struct {
+	CommonName   string   `json:"common_name"`
+	Organization []string `json:"organization,omitempty"`
+	DNSNames     []string `json:"dns_names,omitempty"`
+	Emails       []string `json:"emails,omitempty"`
+	URIs         []string `json:"uris,omitempty"`
+	SerialNumber string   `json:"serial_number"`
+
+	Certificate *x509.Certificate `json:"-"`
+}
+
+// NewClientIdentity returns the identity of the client certificate.
+func NewClientIdentity(cert *x509.Certificate) *ClientIdentity {
+	uris := make([]string, len(cert.URIs))
+	for i, u := range cert.URIs {
+		uris[i] = u.String()
+	}
+
+	return &ClientIdentity{
+		CommonName:   cert.Subject.CommonName,
+		Organization: cert.Subject.Organization,
+		DNSNames:     cert.DNSNames,
+		Emails:       cert.EmailAddresses,
+		URIs:         uris,
+		SerialNumber: cert.SerialNumber.String(),
+		Certificate:  cert,
+	}
+}
+
+// ClientCert is an http middleware to set the identity of the verified TLS
+// client certificate, *ClientIdentity, into the field Auth of httpx.Context,
+// which must be used after the middleware Context.
+//
+// The unverified client certificate is ignored, and Auth is left unchanged
+// if there is no verified client certificate.
+func ClientCert(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			if c := httpx.GetContext(r.Context()); c != nil {
+				c.Auth = NewClientIdentity(r.TLS.VerifiedChains[0][0])
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
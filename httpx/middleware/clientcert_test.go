@@ -0,0 +1,78 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/xgfone/go-toolkit/httpx"
+)
+
+func TestClientCert(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: "client", Organization: []string{"org"}},
+		SerialNumber: big.NewInt(123),
+		URIs:         []*url.URL{{Scheme: "spiffe", Host: "example.org", Path: "/client"}},
+	}
+
+	var auth any
+	handler := Context(ClientCert(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = httpx.GetContext(r.Context()).Auth
+	})))
+
+	t.Run("unverified", func(t *testing.T) {
+		auth = nil
+		req := httptest.NewRequest("GET", "https://127.0.0.1", nil)
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if auth != nil {
+			t.Errorf("expect no auth, but got %v", auth)
+		}
+	})
+
+	t.Run("verified", func(t *testing.T) {
+		auth = nil
+		req := httptest.NewRequest("GET", "https://127.0.0.1", nil)
+		req.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		id, ok := auth.(*ClientIdentity)
+		if !ok {
+			t.Fatalf("expect a *ClientIdentity, but got %T", auth)
+		}
+		if id.CommonName != "client" {
+			t.Errorf("expect common name '%s', but got '%s'", "client", id.CommonName)
+		}
+		if id.SerialNumber != "123" {
+			t.Errorf("expect serial number '%s', but got '%s'", "123", id.SerialNumber)
+		}
+		if len(id.URIs) != 1 || id.URIs[0] != "spiffe://example.org/client" {
+			t.Errorf("unexpected uris: %v", id.URIs)
+		}
+		if id.Certificate != cert {
+			t.Errorf("expect the client certificate, but got %v", id.Certificate)
+		}
+	})
+}
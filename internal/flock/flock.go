@@ -0,0 +1,39 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flock provides the advisory lock of the file across processes.
+package flock
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrLocked is returned when the file has been locked by another.
+var ErrLocked = errors.New("flock: the file has been locked")
+
+// TryLock tries to acquire the exclusive lock of the file without blocking.
+//
+// If the lock has been held by another file descriptor,
+// even in the same process, return ErrLocked.
+func TryLock(f *os.File) error {
+	return tryLock(f)
+}
+
+// Unlock releases the lock of the file acquired by TryLock.
+//
+// Closing the file also releases the lock.
+func Unlock(f *os.File) error {
+	return unlock(f)
+}
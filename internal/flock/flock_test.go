@@ -0,0 +1,56 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTryLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	f1, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f1.Close()
+
+	f2, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Close()
+
+	if err := TryLock(f1); errors.Is(err, errors.ErrUnsupported) {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatalf("expect nil, but got %v", err)
+	}
+
+	if err := TryLock(f2); !errors.Is(err, ErrLocked) {
+		t.Errorf("expect error %v, but got %v", ErrLocked, err)
+	}
+
+	if err := Unlock(f1); err != nil {
+		t.Fatalf("fail to unlock: %v", err)
+	}
+
+	if err := TryLock(f2); err != nil {
+		t.Errorf("expect nil after unlocking, but got %v", err)
+	}
+}
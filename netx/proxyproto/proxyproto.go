@@ -0,0 +1,374 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package proxyproto provides a listener to parse the PROXY protocol
+// v1 and v2 header sent by the load balancer, such as HAProxy and AWS NLB.
+//
+// See https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt.
+//
+// Example:
+//
+//	trusted, err := proxyproto.ParseTrusted("10.0.0.0/8", "192.168.1.10")
+//	if err != nil {
+//		panic(err)
+//	}
+//
+//	server := module.NewHttpServer("api", getAddr, handler)
+//	server.WrapListener(proxyproto.Wrap(trusted, 0))
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHeaderTimeout is the default timeout to read the PROXY protocol header.
+var DefaultHeaderTimeout = time.Second * 10
+
+var (
+	v1Prefix    = []byte("PROXY ")
+	v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// ErrInvalidHeader is returned when the PROXY protocol header is invalid.
+var ErrInvalidHeader = errors.New("proxyproto: invalid header")
+
+// ParseTrusted parses the trusted sources, each of which is a CIDR
+// or an IP address.
+func ParseTrusted(sources ...string) (trusted []netip.Prefix, err error) {
+	trusted = make([]netip.Prefix, 0, len(sources))
+	for _, source := range sources {
+		var prefix netip.Prefix
+		if strings.IndexByte(source, '/') > -1 {
+			prefix, err = netip.ParsePrefix(source)
+		} else {
+			var addr netip.Addr
+			if addr, err = netip.ParseAddr(source); err == nil {
+				prefix = netip.PrefixFrom(addr, addr.BitLen())
+			}
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("proxyproto: invalid trusted source '%s': %w", source, err)
+		}
+		trusted = append(trusted, prefix.Masked())
+	}
+	return
+}
+
+// Wrap returns a function to wrap the listener by NewListener,
+// which is used by HttpServer.WrapListener of the package app/module.
+func Wrap(trusted []netip.Prefix, timeout time.Duration) func(net.Listener) net.Listener {
+	return func(ln net.Listener) net.Listener { return NewListener(ln, trusted, timeout) }
+}
+
+// NewListener returns a new listener which parses the PROXY protocol header
+// of the connections from the trusted sources, and the connections from
+// others are returned as they are.
+//
+// The header of a trusted connection is required, and it is read when
+// reading the data or getting the address from the connection first, not in
+// Accept, so a slow client does not block accepting the others. If failing to
+// read the header within timeout, reading the connection returns the error.
+// If timeout is 0, use DefaultHeaderTimeout instead.
+//
+// trusted must not be empty. To trust all the sources, use "0.0.0.0/0"
+// and "::/0", which is not recommended.
+func NewListener(ln net.Listener, trusted []netip.Prefix, timeout time.Duration) *Listener {
+	if len(trusted) == 0 {
+		panic("proxyproto: the trusted sources must not be empty")
+	}
+	if timeout <= 0 {
+		timeout = DefaultHeaderTimeout
+	}
+	return &Listener{Listener: ln, trusted: trusted, timeout: timeout}
+}
+
+// Listener is a listener to parse the PROXY protocol header.
+type Listener struct {
+	net.Listener
+	trusted []netip.Prefix
+	timeout time.Duration
+}
+
+// Accept waits for and returns the next connection, which is a *Conn
+// if it is from the trusted sources.
+func (l *Listener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil || !l.isTrusted(c.RemoteAddr()) {
+		return c, err
+	}
+	return newConn(c, l.timeout), nil
+}
+
+func (l *Listener) isTrusted(addr net.Addr) bool {
+	ap, err := netip.ParseAddrPort(addr.String())
+	if err != nil {
+		return false // Such as the unix socket.
+	}
+
+	ip := ap.Addr().Unmap()
+	for _, prefix := range l.trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Conn is a connection with the PROXY protocol header.
+type Conn struct {
+	net.Conn
+	timeout time.Duration
+
+	once   sync.Once
+	reader io.Reader
+	err    error
+	src    net.Addr
+	dst    net.Addr
+
+	lock     sync.Mutex
+	deadline time.Time // The read deadline set by the user.
+}
+
+func newConn(c net.Conn, timeout time.Duration) *Conn {
+	return &Conn{Conn: c, timeout: timeout}
+}
+
+// Read reads the data after the PROXY protocol header.
+func (c *Conn) Read(b []byte) (int, error) {
+	if err := c.init(); err != nil {
+		return 0, err
+	}
+	return c.reader.Read(b)
+}
+
+// RemoteAddr returns the source address in the PROXY protocol header,
+// or the original remote address if the header has no address or fails.
+func (c *Conn) RemoteAddr() net.Addr {
+	if c.init() == nil && c.src != nil {
+		return c.src
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr returns the destination address in the PROXY protocol header,
+// or the original local address if the header has no address or fails.
+func (c *Conn) LocalAddr() net.Addr {
+	if c.init() == nil && c.dst != nil {
+		return c.dst
+	}
+	return c.Conn.LocalAddr()
+}
+
+// SetDeadline sets the read and write deadlines.
+func (c *Conn) SetDeadline(t time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.deadline = t
+	return c.Conn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.deadline = t
+	return c.Conn.SetReadDeadline(t)
+}
+
+// Header reads the PROXY protocol header if not, and returns the error.
+func (c *Conn) Header() error {
+	return c.init()
+}
+
+func (c *Conn) init() error {
+	c.once.Do(c.readHeader)
+	return c.err
+}
+
+func (c *Conn) readHeader() {
+	// Limit the time to read the header, then restore the deadline set by the user.
+	c.lock.Lock()
+	deadline := time.Now().Add(c.timeout)
+	if !c.deadline.IsZero() && c.deadline.Before(deadline) {
+		deadline = c.deadline
+	}
+	_ = c.Conn.SetReadDeadline(deadline)
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		_ = c.Conn.SetReadDeadline(c.deadline)
+		c.lock.Unlock()
+	}()
+
+	r := bufio.NewReaderSize(c.Conn, 256)
+	if c.src, c.dst, c.err = readHeader(r); c.err != nil {
+		_ = c.Conn.Close()
+		return
+	}
+
+	if r.Buffered() > 0 {
+		c.reader = io.MultiReader(io.LimitReader(r, int64(r.Buffered())), c.Conn)
+	} else {
+		c.reader = c.Conn
+	}
+}
+
+func readHeader(r *bufio.Reader) (src, dst net.Addr, err error) {
+	sig, err := r.Peek(len(v2Signature))
+	switch {
+	case err != nil:
+		return nil, nil, fmt.Errorf("proxyproto: fail to read the header: %w", err)
+	case bytes.Equal(sig, v2Signature):
+		return readV2(r)
+	case bytes.HasPrefix(sig, v1Prefix):
+		return readV1(r)
+	default:
+		return nil, nil, fmt.Errorf("%w: missing the header", ErrInvalidHeader)
+	}
+}
+
+// readV1 reads the header of the v1 text format, such as
+// "PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n".
+func readV1(r *bufio.Reader) (src, dst net.Addr, err error) {
+	// The maximum length of the v1 header is 107 bytes.
+	const maxlen = 107
+
+	var line []byte
+	for len(line) < maxlen {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, fmt.Errorf("proxyproto: fail to read the v1 header: %w", err)
+		}
+
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, nil, fmt.Errorf("%w: the v1 header is too long or not ended with CRLF", ErrInvalidHeader)
+	}
+
+	fields := strings.Split(string(line[len(v1Prefix):len(line)-2]), " ")
+	switch fields[0] {
+	case "UNKNOWN":
+		return nil, nil, nil
+
+	case "TCP4", "TCP6":
+		if len(fields) != 5 {
+			return nil, nil, fmt.Errorf("%w: invalid v1 header '%s'", ErrInvalidHeader, line[:len(line)-2])
+		}
+
+		srcip, err1 := netip.ParseAddr(fields[1])
+		dstip, err2 := netip.ParseAddr(fields[2])
+		srcport, err3 := parsePort(fields[3])
+		dstport, err4 := parsePort(fields[4])
+		if err = errors.Join(err1, err2, err3, err4); err != nil {
+			return nil, nil, fmt.Errorf("%w: invalid v1 header '%s': %w", ErrInvalidHeader, line[:len(line)-2], err)
+		}
+
+		if is4 := fields[0] == "TCP4"; srcip.Is4() != is4 || dstip.Is4() != is4 {
+			return nil, nil, fmt.Errorf("%w: the address family mismatches in v1 header '%s'",
+				ErrInvalidHeader, line[:len(line)-2])
+		}
+
+		src = net.TCPAddrFromAddrPort(netip.AddrPortFrom(srcip, srcport))
+		dst = net.TCPAddrFromAddrPort(netip.AddrPortFrom(dstip, dstport))
+		return src, dst, nil
+
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown v1 protocol '%s'", ErrInvalidHeader, fields[0])
+	}
+}
+
+func parsePort(s string) (uint16, error) {
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("invalid port '%s'", s)
+	}
+
+	port, err := strconv.ParseUint(s, 10, 16)
+	return uint16(port), err
+}
+
+// readV2 reads the header of the v2 binary format.
+func readV2(r *bufio.Reader) (src, dst net.Addr, err error) {
+	var header [16]byte
+	if _, err = io.ReadFull(r, header[:]); err != nil {
+		return nil, nil, fmt.Errorf("proxyproto: fail to read the v2 header: %w", err)
+	}
+
+	version, command := header[12]>>4, header[12]&0x0F
+	family, transport := header[13]>>4, header[13]&0x0F
+	length := int(binary.BigEndian.Uint16(header[14:16]))
+
+	if version != 2 {
+		return nil, nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidHeader, version)
+	}
+
+	payload := make([]byte, length)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return nil, nil, fmt.Errorf("proxyproto: fail to read the v2 header: %w", err)
+	}
+
+	switch command {
+	case 0x0: // LOCAL, such as the health check by the proxy itself.
+		return nil, nil, nil
+	case 0x1: // PROXY
+	default:
+		return nil, nil, fmt.Errorf("%w: unsupported command %d", ErrInvalidHeader, command)
+	}
+
+	// Only the TCP over IPv4 and IPv6 is supported, and the others,
+	// such as UDP, unix socket and TLVs, are ignored.
+	if transport != 0x1 {
+		return nil, nil, nil
+	}
+
+	var size int
+	switch family {
+	case 0x1: // AF_INET
+		size = 4
+	case 0x2: // AF_INET6
+		size = 16
+	default:
+		return nil, nil, nil
+	}
+
+	if length < size*2+4 {
+		return nil, nil, fmt.Errorf("%w: the v2 address is too short", ErrInvalidHeader)
+	}
+
+	srcip, _ := netip.AddrFromSlice(payload[:size])
+	dstip, _ := netip.AddrFromSlice(payload[size : size*2])
+	srcport := binary.BigEndian.Uint16(payload[size*2:])
+	dstport := binary.BigEndian.Uint16(payload[size*2+2:])
+
+	src = net.TCPAddrFromAddrPort(netip.AddrPortFrom(srcip, srcport))
+	dst = net.TCPAddrFromAddrPort(netip.AddrPortFrom(dstip, dstport))
+	return src, dst, nil
+}
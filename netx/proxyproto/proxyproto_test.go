@@ -0,0 +1,295 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxyproto
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func v2Header(command, family byte, addrs []byte) []byte {
+	header := append([]byte{}, v2Signature...)
+	header = append(header, 0x20|command, family)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(addrs)))
+	return append(header, addrs...)
+}
+
+func v2TCP4(src, dst string, sport, dport uint16) []byte {
+	addrs := append(netip.MustParseAddr(src).AsSlice(), netip.MustParseAddr(dst).AsSlice()...)
+	addrs = binary.BigEndian.AppendUint16(addrs, sport)
+	addrs = binary.BigEndian.AppendUint16(addrs, dport)
+	return v2Header(0x1, 0x11, addrs)
+}
+
+func v2TCP6(src, dst string, sport, dport uint16) []byte {
+	addrs := append(netip.MustParseAddr(src).AsSlice(), netip.MustParseAddr(dst).AsSlice()...)
+	addrs = binary.BigEndian.AppendUint16(addrs, sport)
+	addrs = binary.BigEndian.AppendUint16(addrs, dport)
+	addrs = append(addrs, 0x04, 0x00, 0x01, 'x') // A TLV to be ignored.
+	return v2Header(0x1, 0x21, addrs)
+}
+
+func TestParseTrusted(t *testing.T) {
+	trusted, err := ParseTrusted("10.0.0.0/8", "192.168.1.10", "fd00::1/64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expects := []string{"10.0.0.0/8", "192.168.1.10/32", "fd00::/64"}
+	for i, prefix := range trusted {
+		if s := prefix.String(); s != expects[i] {
+			t.Errorf("%d: expect '%s', but got '%s'", i, expects[i], s)
+		}
+	}
+
+	if _, err := ParseTrusted("10.0.0.0/33"); err == nil {
+		t.Error("expect an error for the invalid cidr, but got nil")
+	}
+	if _, err := ParseTrusted("localhost"); err == nil {
+		t.Error("expect an error for the invalid ip, but got nil")
+	}
+}
+
+// dial sends data to the listener, and returns the accepted connection.
+func dial(t *testing.T, ln net.Listener, data []byte) net.Conn {
+	t.Helper()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	if len(data) > 0 {
+		if _, err = client.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func listen(t *testing.T, trusted ...string) net.Listener {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	prefixes, err := ParseTrusted(trusted...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewListener(ln, prefixes, time.Millisecond*200)
+}
+
+func TestListener(t *testing.T) {
+	ln := listen(t, "127.0.0.0/8")
+
+	tests := []struct {
+		name   string
+		header []byte
+		src    string
+		dst    string
+	}{
+		{
+			name:   "v1-tcp4",
+			header: []byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n"),
+			src:    "192.168.0.1:56324",
+			dst:    "192.168.0.11:443",
+		},
+		{
+			name:   "v1-tcp6",
+			header: []byte("PROXY TCP6 2001:db8::1 2001:db8::2 56324 443\r\n"),
+			src:    "[2001:db8::1]:56324",
+			dst:    "[2001:db8::2]:443",
+		},
+		{
+			name:   "v1-unknown",
+			header: []byte("PROXY UNKNOWN ffff::1 ffff::2 1 2\r\n"),
+		},
+		{
+			name:   "v2-tcp4",
+			header: v2TCP4("10.1.2.3", "10.0.0.1", 12345, 80),
+			src:    "10.1.2.3:12345",
+			dst:    "10.0.0.1:80",
+		},
+		{
+			name:   "v2-tcp6",
+			header: v2TCP6("2001:db8::1", "2001:db8::2", 12345, 80),
+			src:    "[2001:db8::1]:12345",
+			dst:    "[2001:db8::2]:80",
+		},
+		{
+			name:   "v2-local",
+			header: v2Header(0x0, 0x00, nil),
+		},
+		{
+			name:   "v2-unix",
+			header: v2Header(0x1, 0x31, make([]byte, 216)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := dial(t, ln, append(tt.header, "hello"...))
+			if _, ok := conn.(*Conn); !ok {
+				t.Fatalf("expect a *Conn, but got %T", conn)
+			}
+
+			src, dst := tt.src, tt.dst
+			if src == "" {
+				src = conn.(*Conn).Conn.RemoteAddr().String()
+				dst = conn.(*Conn).Conn.LocalAddr().String()
+			}
+
+			if addr := conn.RemoteAddr().String(); addr != src {
+				t.Errorf("expect remote addr '%s', but got '%s'", src, addr)
+			}
+			if addr := conn.LocalAddr().String(); addr != dst {
+				t.Errorf("expect local addr '%s', but got '%s'", dst, addr)
+			}
+
+			buf := make([]byte, 5)
+			if _, err := io.ReadFull(conn, buf); err != nil {
+				t.Fatal(err)
+			} else if string(buf) != "hello" {
+				t.Errorf("expect data '%s', but got '%s'", "hello", buf)
+			}
+		})
+	}
+}
+
+func TestListenerInvalidHeader(t *testing.T) {
+	ln := listen(t, "127.0.0.1")
+
+	for _, header := range []string{
+		"GET / HTTP/1.1\r\n\r\n",
+		"PROXY TCP4 192.168.0.1 192.168.0.11 56324\r\n",
+		"PROXY TCP4 2001:db8::1 192.168.0.11 56324 443\r\n",
+		"PROXY TCP4 192.168.0.1 192.168.0.11 56324 65536\r\n",
+		"PROXY UDP4 192.168.0.1 192.168.0.11 56324 443\r\n",
+		"PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\n",
+		string(v2Header(0x1, 0x11, make([]byte, 4))),
+		string(v2Header(0x2, 0x11, nil)),
+	} {
+		conn := dial(t, ln, []byte(header))
+		if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, ErrInvalidHeader) {
+			t.Errorf("%q: expect error ErrInvalidHeader, but got %v", header, err)
+		}
+		if addr, orig := conn.RemoteAddr(), conn.(*Conn).Conn.RemoteAddr(); addr != orig {
+			t.Errorf("%q: expect the original remote addr, but got '%s'", header, addr)
+		}
+	}
+}
+
+func TestListenerHeaderTimeout(t *testing.T) {
+	ln := listen(t, "127.0.0.1")
+
+	conn := dial(t, ln, []byte("PROXY TCP4"))
+	start := time.Now()
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Fatal("expect a timeout error, but got nil")
+	} else if cost := time.Since(start); cost > time.Second*2 {
+		t.Errorf("expect timeout after %s, but got %s", time.Millisecond*200, cost)
+	}
+}
+
+func TestListenerRestoreDeadline(t *testing.T) {
+	ln := listen(t, "127.0.0.1")
+
+	conn := dial(t, ln, []byte("PROXY UNKNOWN\r\n"))
+	_ = conn.SetReadDeadline(time.Now().Add(time.Millisecond * 500))
+	if err := conn.(*Conn).Header(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The deadline set by the user is still effective after reading the header,
+	// not the header timeout which is shorter, or no deadline.
+	start := time.Now()
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Fatal("expect a timeout error, but got nil")
+	} else if cost := time.Since(start); cost > time.Second*2 {
+		t.Errorf("expect the read deadline to be restored, but waited %s", cost)
+	}
+}
+
+func TestListenerUntrusted(t *testing.T) {
+	ln := listen(t, "10.0.0.0/8")
+
+	conn := dial(t, ln, []byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n"))
+	if _, ok := conn.(*Conn); ok {
+		t.Fatal("expect the original connection from the untrusted source")
+	}
+
+	buf := make([]byte, 6)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatal(err)
+	} else if string(buf) != "PROXY " {
+		t.Errorf("expect the raw data, but got '%s'", buf)
+	}
+}
+
+func TestListenerHTTP(t *testing.T) {
+	ln := listen(t, "127.0.0.1")
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.RemoteAddr)
+	})}
+	go server.Serve(ln)
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := new(net.Dialer).DialContext(ctx, network, addr)
+			if err == nil {
+				_, err = conn.Write(v2TCP4("203.0.113.7", "10.0.0.1", 4567, 80))
+			}
+			return conn, err
+		},
+	}}
+
+	resp, err := client.Get("http://" + ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if body, _ := io.ReadAll(resp.Body); string(body) != "203.0.113.7:4567" {
+		t.Errorf("expect remote addr '%s', but got '%s'", "203.0.113.7:4567", body)
+	}
+}
+
+func TestNewListenerPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expect a panic without the trusted sources")
+		}
+	}()
+	NewListener(nil, nil, 0)
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package structx
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/xgfone/go-toolkit/internal/structs"
+	"github.com/xgfone/go-toolkit/reflectx"
+)
+
+var (
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	strEncodeParser   = structs.NewParser(compileFieldEncoder, isTextMarshalerField)
+)
+
+type fieldEncoder struct {
+	encode valueEncoder
+	tag    reflect.StructTag
+}
+
+type valueEncoder func(v reflect.Value) (string, error)
+
+// EncodeValues is the inverse of BindValues, which encodes the exported
+// fields of the struct src into url.Values based on the field tag name.
+//
+// src must be a struct or a non-nil pointer to struct. The field names and
+// the nested struct expansion are the same as BindValues. The field whose
+// type implements encoding.TextMarshaler is encoded by MarshalText,
+// and the slice or array field is encoded as the multiple values.
+//
+// The nil pointer field is omitted. Add the "omitempty" tag option to omit
+// the field with the zero value, for example `q:"field,omitempty"`.
+func EncodeValues(src any, tag string) (url.Values, error) {
+	root := reflect.ValueOf(src)
+	if root.Kind() == reflect.Pointer {
+		if root.IsNil() {
+			return nil, errors.New("src is nil")
+		}
+		root = root.Elem()
+	}
+	if root.Kind() != reflect.Struct {
+		return nil, errors.New("src is not a struct or a pointer to struct")
+	}
+
+	values := make(url.Values)
+	for _, f := range strEncodeParser.Parse(root.Type(), tag).Fields {
+		v := structs.GetFieldByIndex(root, f.Indexes, false)
+		if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+			continue
+		}
+		if v.IsZero() && hasTagOption(f.Data.tag.Get(tag), "omitempty") {
+			continue
+		}
+
+		if err := encodeField(values, f.Name, v, f.Data.encode); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+
+	return values, nil
+}
+
+func encodeField(values url.Values, name string, v reflect.Value, encode valueEncoder) error {
+	switch {
+	case encode != nil:
+		s, err := encode(v)
+		if err != nil {
+			return err
+		}
+		values.Add(name, s)
+
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		elemEncode := compileValueEncoder(v.Type().Elem())
+		if elemEncode == nil {
+			return fmt.Errorf("unsupported field type %s", v.Type())
+		}
+
+		for i, _len := 0, v.Len(); i < _len; i++ {
+			if elem := v.Index(i); elem.Kind() != reflect.Pointer || !elem.IsNil() {
+				s, err := elemEncode(elem)
+				if err != nil {
+					return err
+				}
+				values.Add(name, s)
+			}
+		}
+
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+
+	return nil
+}
+
+func compileFieldEncoder(sf reflect.StructField) fieldEncoder {
+	return fieldEncoder{encode: compileValueEncoder(sf.Type), tag: sf.Tag}
+}
+
+// compileValueEncoder returns the encoder of the type, or nil if unsupported.
+func compileValueEncoder(t reflect.Type) valueEncoder {
+	if reflectx.Implements(t, textMarshalerType) {
+		return encodeText
+	}
+
+	if t.Kind() == reflect.Pointer {
+		if elemEncode := compileValueEncoder(t.Elem()); elemEncode != nil {
+			return func(v reflect.Value) (string, error) { return elemEncode(v.Elem()) }
+		}
+		return nil
+	}
+
+	if reflectx.Implements(reflect.PointerTo(t), textMarshalerType) {
+		return encodeAddrText
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(v reflect.Value) (string, error) { return v.String(), nil }
+
+	case reflect.Bool:
+		return func(v reflect.Value) (string, error) { return strconv.FormatBool(v.Bool()), nil }
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value) (string, error) { return strconv.FormatInt(v.Int(), 10), nil }
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(v reflect.Value) (string, error) { return strconv.FormatUint(v.Uint(), 10), nil }
+
+	case reflect.Float32, reflect.Float64:
+		return func(v reflect.Value) (string, error) {
+			return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+		}
+
+	default:
+		return nil
+	}
+}
+
+func encodeText(v reflect.Value) (string, error) {
+	data, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	return string(data), err
+}
+
+// encodeAddrText encodes the value whose pointer implements encoding.TextMarshaler.
+func encodeAddrText(v reflect.Value) (string, error) {
+	if !v.CanAddr() {
+		tmp := reflect.New(v.Type()).Elem()
+		tmp.Set(v)
+		v = tmp
+	}
+	return encodeText(v.Addr())
+}
+
+func isTextMarshalerField(sf reflect.StructField) bool {
+	t := sf.Type
+	if t.Kind() != reflect.Pointer {
+		t = reflect.PointerTo(t)
+	}
+	return reflectx.Implements(t, textMarshalerType)
+}
+
+func hasTagOption(tag, option string) bool {
+	_, options, _ := strings.Cut(tag, ",")
+	for options != "" {
+		var opt string
+		if opt, options, _ = strings.Cut(options, ","); opt == option {
+			return true
+		}
+	}
+	return false
+}
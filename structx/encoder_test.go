@@ -0,0 +1,95 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package structx
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type encodeInner struct {
+	Page int `q:"page"`
+	Size int `q:"size,omitempty"`
+}
+
+func TestEncodeValues(t *testing.T) {
+	type Query struct {
+		encodeInner
+
+		Name    string    `q:"name"`
+		Enabled bool      `q:"enabled"`
+		Ratio   float64   `q:"ratio"`
+		Limit   *uint     `q:"limit"`
+		Offset  *int      `q:"offset"`
+		Tags    []string  `q:"tag"`
+		Since   time.Time `q:"since,omitempty"`
+		Until   time.Time `q:"until"`
+		Skip    string    `q:"-"`
+		Empty   string    `q:"empty,omitempty"`
+
+		ignore string
+	}
+
+	limit := uint(10)
+	until := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	q := Query{
+		encodeInner: encodeInner{Page: 2},
+		Name:        "abc",
+		Enabled:     true,
+		Ratio:       1.5,
+		Limit:       &limit,
+		Tags:        []string{"a", "b"},
+		Until:       until,
+		Skip:        "skip",
+		ignore:      "ignore",
+	}
+
+	values, err := EncodeValues(&q, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := url.Values{
+		"page":    {"2"},
+		"name":    {"abc"},
+		"enabled": {"true"},
+		"ratio":   {"1.5"},
+		"limit":   {"10"},
+		"tag":     {"a", "b"},
+		"until":   {"2026-01-02T03:04:05Z"},
+	}
+	if !reflect.DeepEqual(values, expect) {
+		t.Errorf("expect %v, but got %v", expect, values)
+	}
+
+	var dst encodeInner
+	if err := BindValues(&dst, values, "q"); err != nil {
+		t.Fatal(err)
+	} else if dst != q.encodeInner {
+		t.Errorf("expect %+v, but got %+v", q.encodeInner, dst)
+	}
+
+	if _, err := EncodeValues(1, "q"); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+	if _, err := EncodeValues((*Query)(nil), "q"); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+	if _, err := EncodeValues(struct{ M map[string]int }{M: map[string]int{}}, ""); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+}